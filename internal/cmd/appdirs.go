@@ -0,0 +1,113 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// appDirDepth is how many directory levels below an --app-dir are searched for bundles,
+// enough for a folder of category subfolders without crawling a whole external volume.
+const appDirDepth = 3
+
+// appDirCount is the number of app bundles found in one --app-dir directory.
+type appDirCount struct {
+	Path  string `json:"path" yaml:"path" toml:"path" xml:"path"`
+	Count int    `json:"count" yaml:"count" toml:"count" xml:"count"`
+}
+
+// findAppBundles walks dir for .app bundles, without descending into the bundles
+// themselves. Unreadable subdirectories are skipped.
+func findAppBundles(dir string, maxDepth int) ([]string, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, err
+	}
+	var bundles []string
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			if path == dir {
+				return walkErr
+			}
+			return filepath.SkipDir
+		}
+		if !d.IsDir() || path == dir {
+			return nil
+		}
+		if strings.HasSuffix(d.Name(), ".app") {
+			bundles = append(bundles, path)
+			return filepath.SkipDir
+		}
+		if strings.Count(strings.TrimPrefix(path, dir), string(os.PathSeparator)) >= maxDepth {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortNatural(bundles)
+	return bundles, nil
+}
+
+// writeExtraAppDirs lists the bundles in each --app-dir under its own heading and merges
+// them into the result's app list. Missing directories are warnings.
+func (r *exportRun) writeExtraAppDirs() error {
+	known := make(map[string]bool, len(r.result.Apps))
+	for _, app := range r.result.Apps {
+		known[app] = true
+	}
+	merged := append([]string(nil), r.result.Apps...)
+
+	for _, dir := range r.opts.appDirs {
+		if _, err := fmt.Fprintf(r.w, "\n-- %s ---\n", r.displayPath(dir)); err != nil {
+			return err
+		}
+		bundles, err := findAppBundles(dir, appDirDepth)
+		if err != nil {
+			if err := r.strictFail("--app-dir %s: %v", dir, err); err != nil {
+				return err
+			}
+			r.warn(fmt.Sprintf("--app-dir %s skipped: %v", dir, err))
+			if _, err := fmt.Fprintln(r.w, "(not readable; see warnings)"); err != nil {
+				return err
+			}
+			continue
+		}
+		kept := bundles[:0]
+		for _, bundle := range bundles {
+			if r.opts.appExcludes.ignored(bundle) {
+				continue
+			}
+			if underAny(bundle, r.opts.systemApps) {
+				r.stats.ExcludedSystemAppCount++
+				continue
+			}
+			kept = append(kept, bundle)
+		}
+		bundles = kept
+		r.result.AppDirCounts = append(r.result.AppDirCounts, appDirCount{Path: dir, Count: len(bundles)})
+		for _, bundle := range bundles {
+			if !known[bundle] {
+				known[bundle] = true
+				merged = append(merged, bundle)
+			}
+		}
+		if err := writeLines(r.w, r.displayPaths(bundles)); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(r.w, "(%d app bundles)\n", len(bundles)); err != nil {
+			return err
+		}
+	}
+
+	if !r.opts.noSort {
+		sortNatural(merged)
+	}
+	r.result.Apps = merged
+	r.stats.AppBundleCount = len(merged)
+	return nil
+}
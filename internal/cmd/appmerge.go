@@ -0,0 +1,64 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"path/filepath"
+)
+
+// appConflicts returns the entries present in both /Applications and ~/Applications,
+// in systemApps order.
+func appConflicts(systemApps, userApps []string) []string {
+	inUser := make(map[string]bool, len(userApps))
+	for _, name := range userApps {
+		inUser[name] = true
+	}
+	var conflicts []string
+	for _, name := range systemApps {
+		if inUser[name] {
+			conflicts = append(conflicts, name)
+		}
+	}
+	return conflicts
+}
+
+// mergeUserApps applies --merge-user-and-system-apps to the bundle list: for every app
+// in both Applications folders only the /Applications copy is kept. The conflicting
+// names are recorded in the result.
+func (r *exportRun) mergeUserApps(bundles []string) []string {
+	systemApps, _ := listDirSorted("/Applications")
+	userDir := filepath.Join(r.homeDir, "Applications")
+	userApps, _ := listDirSorted(userDir)
+	conflicts := appConflicts(systemApps, userApps)
+	r.result.AppConflicts = conflicts
+	r.stats.AppConflictCount = len(conflicts)
+	if len(conflicts) == 0 {
+		return bundles
+	}
+
+	shadowed := make(map[string]bool, len(conflicts))
+	for _, name := range conflicts {
+		shadowed[filepath.Join(userDir, name)] = true
+	}
+	kept := make([]string, 0, len(bundles))
+	for _, bundle := range bundles {
+		if !shadowed[bundle] {
+			kept = append(kept, bundle)
+		}
+	}
+	return kept
+}
+
+// appConflictSet returns the names recorded by mergeUserApps, or nil when the lists
+// are not being merged.
+func (r *exportRun) appConflictSet() map[string]bool {
+	if !r.opts.mergeApps {
+		return nil
+	}
+	set := make(map[string]bool, len(r.result.AppConflicts))
+	for _, name := range r.result.AppConflicts {
+		set[name] = true
+	}
+	return set
+}
@@ -0,0 +1,26 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAppConflicts(t *testing.T) {
+	tests := []struct {
+		system, user []string
+		want         []string
+	}{
+		{nil, nil, nil},
+		{[]string{"Arc.app", "Zed.app"}, nil, nil},
+		{[]string{"Arc.app", "Zed.app"}, []string{"Notes.app"}, nil},
+		{[]string{"Zed.app", "Arc.app", "Slack.app"}, []string{"Arc.app", "Zed.app"}, []string{"Zed.app", "Arc.app"}},
+	}
+	for _, tt := range tests {
+		if got := appConflicts(tt.system, tt.user); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("appConflicts(%q, %q) = %q, want %q", tt.system, tt.user, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	archARM64     = "arm64"
+	archX86       = "x86_64"
+	archUniversal = "universal"
+	archMixed     = "mixed"
+	archUnknown   = "unknown"
+)
+
+// writeArchSection tags each app bundle and formula with the architectures its
+// executables support and returns how many entries are Intel-only. label formats each
+// bundle path for display. Up to workers binaries are inspected at once.
+func writeArchSection(ctx context.Context, w io.Writer, apps []string, formulae []string, prefix string, label func(string) string, workers int) (int, error) {
+	intelOnly := 0
+
+	if _, err := fmt.Fprintln(w, "-- App bundles --"); err != nil {
+		return intelOnly, err
+	}
+	appArchs := parallelMap(workers, apps, func(bundle string) string { return appArch(ctx, bundle) })
+	for i, bundle := range apps {
+		arch := appArchs[i]
+		if arch == archX86 {
+			intelOnly++
+		}
+		if _, err := fmt.Fprintf(w, "%s [%s]\n", label(bundle), arch); err != nil {
+			return intelOnly, err
+		}
+	}
+
+	if _, err := fmt.Fprintln(w); err != nil {
+		return intelOnly, err
+	}
+	if _, err := fmt.Fprintln(w, "-- Formulae --"); err != nil {
+		return intelOnly, err
+	}
+	formulaArchs := parallelMap(workers, formulae, func(name string) string { return formulaArch(ctx, prefix, name) })
+	for i, name := range formulae {
+		arch := formulaArchs[i]
+		if arch == archX86 {
+			intelOnly++
+		}
+		if _, err := fmt.Fprintf(w, "%s [%s]\n", name, arch); err != nil {
+			return intelOnly, err
+		}
+	}
+	return intelOnly, nil
+}
+
+func appArch(ctx context.Context, bundle string) string {
+	plist := filepath.Join(bundle, "Contents", "Info.plist")
+	out, err := execCommand(ctx, "plutil", "-extract", "CFBundleExecutable", "raw", "-o", "-", plist).Output()
+	if err != nil {
+		return archUnknown
+	}
+	exe := strings.TrimSpace(string(out))
+	if exe == "" {
+		return archUnknown
+	}
+	archs, err := binaryArchs(ctx, filepath.Join(bundle, "Contents", "MacOS", exe))
+	if err != nil {
+		return archUnknown
+	}
+	return classifyArchs([][]string{archs})
+}
+
+// formulaArch inspects the Mach-O binaries linked under <prefix>/opt/<name>/bin.
+// Scripts and other non-Mach-O files are ignored.
+func formulaArch(ctx context.Context, prefix, name string) string {
+	if prefix == "" {
+		return archUnknown
+	}
+	binDir := filepath.Join(prefix, "opt", name, "bin")
+	entries, err := listDirSorted(binDir)
+	if err != nil {
+		return archUnknown
+	}
+	var sets [][]string
+	for _, entry := range entries {
+		archs, err := binaryArchs(ctx, filepath.Join(binDir, entry))
+		if err != nil {
+			continue
+		}
+		sets = append(sets, archs)
+	}
+	return classifyArchs(sets)
+}
+
+func binaryArchs(ctx context.Context, path string) ([]string, error) {
+	out, err := execCommand(ctx, "lipo", "-archs", path).Output()
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(out)), nil
+}
+
+// classifyArchs reduces the architecture lists of one or more binaries to the set every
+// binary supports, then names it. Binaries that disagree entirely are reported as mixed.
+func classifyArchs(sets [][]string) string {
+	if len(sets) == 0 {
+		return archUnknown
+	}
+	var arm, intel bool
+	for i, archs := range sets {
+		var setARM, setIntel bool
+		for _, arch := range archs {
+			switch {
+			case strings.HasPrefix(arch, "arm64"):
+				setARM = true
+			case arch == archX86 || arch == "x86_64h":
+				setIntel = true
+			}
+		}
+		if i == 0 {
+			arm, intel = setARM, setIntel
+			continue
+		}
+		arm = arm && setARM
+		intel = intel && setIntel
+	}
+	switch {
+	case arm && intel:
+		return archUniversal
+	case arm:
+		return archARM64
+	case intel:
+		return archX86
+	default:
+		return archMixed
+	}
+}
+
+// onlyPackages keeps the `brew list --versions` lines whose package name is in names.
+func onlyPackages(lines, names []string) []string {
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+	kept := make([]string, 0, len(names))
+	for _, line := range lines {
+		if fields := strings.Fields(line); len(fields) > 0 && wanted[fields[0]] {
+			kept = append(kept, line)
+		}
+	}
+	return kept
+}
+
+// packageNames returns the first field of each `brew list --versions` line.
+func packageNames(lines []string) []string {
+	names := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if fields := strings.Fields(line); len(fields) > 0 {
+			names = append(names, fields[0])
+		}
+	}
+	return names
+}
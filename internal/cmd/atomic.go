@@ -0,0 +1,85 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// atomicFile is written under a temporary name beside its destination and renamed
+// into place by Commit, so readers of the destination never see a partial file.
+// Close without Commit discards it.
+type atomicFile struct {
+	*os.File
+	path      string
+	committed bool
+}
+
+// createAtomic starts an atomic write of path. The temporary file is hidden (".name.*")
+// so report pruning and globbing consumers skip it.
+func createAtomic(path string) (*atomicFile, error) {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return nil, err
+	}
+	// CreateTemp uses 0600; match what os.Create would have produced.
+	if err := f.Chmod(0o644); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, err
+	}
+	return &atomicFile{File: f, path: path}, nil
+}
+
+// Commit closes the file and renames it over the destination.
+func (f *atomicFile) Commit() error {
+	if err := f.File.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if err := os.Rename(f.Name(), f.path); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	f.committed = true
+	return nil
+}
+
+// Close discards an uncommitted file; after Commit it does nothing.
+func (f *atomicFile) Close() error {
+	if f.committed {
+		return nil
+	}
+	f.File.Close()
+	return os.Remove(f.Name())
+}
+
+// copyExisting copies the current contents of path, if any, into f, for --append. It
+// returns the number of bytes copied.
+func copyExisting(f *atomicFile, path string) (int64, error) {
+	existing, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer existing.Close()
+	return io.Copy(f, existing)
+}
+
+// writeFileAtomic copies r to path through an atomicFile.
+func writeFileAtomic(path string, r io.Reader) error {
+	f, err := createAtomic(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := io.Copy(f, r); err != nil {
+		return err
+	}
+	return f.Commit()
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	arcer "github.com/yourorg/arc-sdk/errors"
+)
+
+// baselineDiff is the drift between this export and a saved `--output json` result.
+type baselineDiff struct {
+	Path    string   `json:"path" yaml:"path" toml:"path" xml:"path"`
+	Added   []string `json:"added" yaml:"added" toml:"added" xml:"added>entry"`
+	Removed []string `json:"removed" yaml:"removed" toml:"removed" xml:"removed>entry"`
+}
+
+func loadBaseline(path string) (*exportResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, &arcer.CLIError{
+			Msg:  fmt.Sprintf("read baseline: %v", err),
+			Hint: "Create one with `arc-apps export --output json > baseline.json`.",
+		}
+	}
+	var baseline exportResult
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, &arcer.CLIError{
+			Msg:  fmt.Sprintf("baseline %s is not an export result: %v", path, err),
+			Hint: "Pass a file written by `arc-apps export --output json`.",
+		}
+	}
+	return &baseline, nil
+}
+
+// resultSnapshot lists a saved result's items in the same form as exportRun.snapshot.
+func resultSnapshot(result exportResult) []string {
+	snapshot := make([]string, 0, len(result.Apps)+len(result.Casks)+len(result.Formulae))
+	for _, app := range result.Apps {
+		snapshot = append(snapshot, "app "+app)
+	}
+	for _, cask := range result.Casks {
+		snapshot = append(snapshot, strings.TrimSpace("cask "+cask.Name+" "+cask.version()))
+	}
+	for _, formula := range result.Formulae {
+		snapshot = append(snapshot, strings.TrimSpace("formula "+formula.Name+" "+formula.version()))
+	}
+	return snapshot
+}
+
+func writeBaselineSection(r *exportRun) error {
+	current, err := r.snapshot()
+	if err != nil {
+		return err
+	}
+	diff := diffSnapshots(resultSnapshot(*r.opts.baseline), current)
+	r.result.BaselineDiff = &baselineDiff{
+		Path:    r.opts.baselinePath,
+		Added:   append([]string{}, diff.Added...),
+		Removed: append([]string{}, diff.Removed...),
+	}
+
+	taken := "unknown time"
+	if !r.opts.baseline.CompletedAt.IsZero() {
+		taken = r.opts.baseline.CompletedAt.Format("2006-01-02 15:04:05")
+	}
+	lines := []string{
+		fmt.Sprintf("Baseline: %s (%s, %s)", r.opts.baselinePath, hostnameOf(*r.opts.baseline), taken),
+		"",
+		fmt.Sprintf("-- Added since baseline (%d) --", len(diff.Added)),
+	}
+	for _, entry := range diff.Added {
+		lines = append(lines, "+ "+entry)
+	}
+	lines = append(lines, "", fmt.Sprintf("-- Removed since baseline (%d) --", len(diff.Removed)))
+	for _, entry := range diff.Removed {
+		lines = append(lines, "- "+entry)
+	}
+	return writeLines(r.w, lines)
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// secretEnvMarkers flag HOMEBREW_* variables (such as HOMEBREW_GITHUB_API_TOKEN) whose
+// values must never reach a report.
+var secretEnvMarkers = []string{"TOKEN", "PASSWORD", "SECRET", "KEY", "AUTH"}
+
+// parseBrewConfig reads the "KEY: value" lines of `brew config` ("HOMEBREW_PREFIX:
+// /opt/homebrew", "Core tap JSON: 14 Oct 08:00 UTC") into a map, splitting at the first
+// colon. Lines without one are ignored.
+func parseBrewConfig(text string) stringMap {
+	config := make(stringMap)
+	for _, line := range strings.Split(text, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			continue
+		}
+		config[key] = strings.TrimSpace(value)
+	}
+	return config
+}
+
+// parseBrewAnalyticsState reads `brew analytics state`, which prints a sentence such as
+// "InfluxDB analytics are enabled." or "Analytics are disabled.".
+func parseBrewAnalyticsState(lines []string) (enabled bool, ok bool) {
+	text := strings.ToLower(strings.Join(lines, " "))
+	switch {
+	case strings.Contains(text, "disabled"):
+		return false, true
+	case strings.Contains(text, "enabled"):
+		return true, true
+	}
+	return false, false
+}
+
+// checkBrewAnalytics records whether Homebrew analytics are on. Enabled analytics are
+// also a warning, since they send usage data to Homebrew.
+func (r *exportRun) checkBrewAnalytics() error {
+	lines, err := commandLines(r.ctx, r.brew(), "analytics", "state")
+	if err != nil {
+		r.warn(fmt.Sprintf("brew analytics state failed: %v", err))
+		return nil
+	}
+	enabled, ok := parseBrewAnalyticsState(lines)
+	if !ok {
+		r.warn(fmt.Sprintf("brew analytics state: unrecognized output %q", strings.Join(lines, " ")))
+		return nil
+	}
+	r.result.BrewAnalyticsEnabled = enabled
+	state := "disabled"
+	if enabled {
+		state = "enabled"
+		r.warn("Homebrew analytics are enabled; run `brew analytics off` (or set HOMEBREW_NO_ANALYTICS=1) to opt out")
+	}
+	_, err = fmt.Fprintf(r.w, "Analytics: %s\n\n", state)
+	return err
+}
+
+// brewEnvVars returns the HOMEBREW_* variables from environ ("KEY=value" pairs).
+// Credentials are replaced with "(set)".
+func brewEnvVars(environ []string) stringMap {
+	vars := make(stringMap)
+	for _, kv := range environ {
+		name, value, ok := strings.Cut(kv, "=")
+		if !ok || !strings.HasPrefix(name, "HOMEBREW_") {
+			continue
+		}
+		for _, marker := range secretEnvMarkers {
+			if strings.Contains(name, marker) {
+				value = "(set)"
+				break
+			}
+		}
+		vars[name] = value
+	}
+	return vars
+}
+
+func writeBrewVarsSection(r *exportRun) error {
+	vars := brewEnvVars(os.Environ())
+	r.result.BrewEnv = vars
+	if len(vars) == 0 {
+		_, err := fmt.Fprintln(r.w, "(no HOMEBREW_* variables set)")
+		return err
+	}
+
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, err := fmt.Fprintf(r.w, "%s=%s\n", name, vars[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
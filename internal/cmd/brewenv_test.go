@@ -0,0 +1,27 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import "testing"
+
+func TestParseBrewAnalyticsState(t *testing.T) {
+	tests := []struct {
+		lines       []string
+		wantEnabled bool
+		wantOK      bool
+	}{
+		{[]string{"InfluxDB analytics are enabled."}, true, true},
+		{[]string{"Analytics are disabled."}, false, true},
+		{[]string{"InfluxDB analytics are disabled."}, false, true},
+		{[]string{"Analytics are", "ENABLED."}, true, true},
+		{[]string{"Error: unknown command"}, false, false},
+		{nil, false, false},
+	}
+	for _, tt := range tests {
+		enabled, ok := parseBrewAnalyticsState(tt.lines)
+		if enabled != tt.wantEnabled || ok != tt.wantOK {
+			t.Errorf("parseBrewAnalyticsState(%q) = %v, %v, want %v, %v", tt.lines, enabled, ok, tt.wantEnabled, tt.wantOK)
+		}
+	}
+}
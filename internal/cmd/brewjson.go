@@ -0,0 +1,320 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// brewInfo mirrors the subset of `brew info --installed --json=v2` that arc-apps reads.
+type brewInfo struct {
+	Formulae []brewFormula `json:"formulae"`
+	Casks    []brewCask    `json:"casks"`
+}
+
+type brewFormula struct {
+	Name         string   `json:"name"`
+	FullName     string   `json:"full_name"`
+	Tap          string   `json:"tap"`
+	Dependencies []string `json:"dependencies"`
+	Installed    []struct {
+		Version string `json:"version"`
+	} `json:"installed"`
+	Versions struct {
+		Stable string `json:"stable"`
+	} `json:"versions"`
+	Deprecated        bool   `json:"deprecated"`
+	DeprecationDate   string `json:"deprecation_date"`
+	DeprecationReason string `json:"deprecation_reason"`
+	Disabled          bool   `json:"disabled"`
+	DisableDate       string `json:"disable_date"`
+	DisableReason     string `json:"disable_reason"`
+}
+
+// deprecatedFormula is an installed formula Homebrew has deprecated or disabled.
+type deprecatedFormula struct {
+	Name   string `json:"name" yaml:"name" toml:"name" xml:"name"`
+	Status string `json:"status" yaml:"status" toml:"status" xml:"status"`
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty" toml:"reason,omitempty" xml:"reason,omitempty"`
+	Date   string `json:"date,omitempty" yaml:"date,omitempty" toml:"date,omitempty" xml:"date,omitempty"`
+}
+
+type brewCask struct {
+	Token   string `json:"token"`
+	Tap     string `json:"tap"`
+	Version string `json:"version"`
+	// Installed is the installed version, which can trail Version (the tap's latest).
+	Installed string `json:"installed"`
+	// Artifacts stay raw: recent brew emits {"app": [...]} objects but older
+	// releases used bare arrays, and neither shape should break decoding.
+	Artifacts []json.RawMessage `json:"artifacts"`
+}
+
+func readBrewInfo(path string) (brewInfo, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return brewInfo{}, err
+	}
+	defer file.Close()
+	return decodeBrewInfo(file, path)
+}
+
+// decodeBrewInfo reads `brew info --json=v2` output from r; name labels errors.
+func decodeBrewInfo(r io.Reader, name string) (brewInfo, error) {
+	var info brewInfo
+	if err := json.NewDecoder(r).Decode(&info); err != nil {
+		return info, fmt.Errorf("decode %s: %w", name, err)
+	}
+	return info, nil
+}
+
+// validateBrewJSON checks that path holds one complete `brew info --json=v2` object.
+// brew occasionally exits after printing only part of it.
+func validateBrewJSON(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var doc struct {
+		Formulae *[]json.RawMessage `json:"formulae"`
+		Casks    *[]json.RawMessage `json:"casks"`
+	}
+	if err := json.NewDecoder(file).Decode(&doc); err != nil {
+		return fmt.Errorf("decode %s: %w", path, err)
+	}
+	if doc.Formulae == nil && doc.Casks == nil {
+		return fmt.Errorf("%s has neither formulae nor casks", path)
+	}
+	return nil
+}
+
+// versionLines renders info in `brew list --versions` form so it compares directly
+// with freshly collected lists.
+func (info brewInfo) versionLines() (casks, formulae []string) {
+	for _, c := range info.Casks {
+		casks = append(casks, strings.TrimSpace(c.Token+" "+c.Installed))
+	}
+	for _, f := range info.Formulae {
+		line := f.Name
+		for _, inst := range f.Installed {
+			line += " " + inst.Version
+		}
+		formulae = append(formulae, line)
+	}
+	return casks, formulae
+}
+
+// leafFormulae returns the installed formulae that no other installed formula depends on.
+func leafFormulae(info brewInfo) []string {
+	dependedOn := make(map[string]bool)
+	for _, f := range info.Formulae {
+		for _, dep := range f.Dependencies {
+			dependedOn[dep] = true
+		}
+	}
+
+	var leaves []string
+	for _, f := range info.Formulae {
+		if dependedOn[f.Name] || (f.FullName != "" && dependedOn[f.FullName]) {
+			continue
+		}
+		leaves = append(leaves, f.Name)
+	}
+	sortNatural(leaves)
+	return leaves
+}
+
+// writeDependencySummary appends per-formula dependency counts and the leaf list,
+// returning the number of leaves written. Leaves are computed over every installed
+// formula; filter only limits which ones are listed.
+func writeDependencySummary(w io.Writer, info brewInfo, filter nameFilter) (int, error) {
+	var formulae []brewFormula
+	for _, f := range info.Formulae {
+		if filter.keep(f.Name) {
+			formulae = append(formulae, f)
+		}
+	}
+	sort.Slice(formulae, func(i, j int) bool { return naturalLess(formulae[i].Name, formulae[j].Name) })
+
+	if _, err := fmt.Fprintln(w); err != nil {
+		return 0, err
+	}
+	if _, err := fmt.Fprintln(w, "-- Dependency counts --"); err != nil {
+		return 0, err
+	}
+	for _, f := range formulae {
+		if _, err := fmt.Fprintf(w, "%s (%d deps)\n", f.Name, len(f.Dependencies)); err != nil {
+			return 0, err
+		}
+	}
+
+	var leaves []string
+	for _, name := range leafFormulae(info) {
+		if filter.keep(name) {
+			leaves = append(leaves, name)
+		}
+	}
+	if _, err := fmt.Fprintln(w); err != nil {
+		return 0, err
+	}
+	if _, err := fmt.Fprintln(w, "-- Leaf formulae (no installed dependents) --"); err != nil {
+		return 0, err
+	}
+	if err := writeLines(w, leaves); err != nil {
+		return 0, err
+	}
+	return len(leaves), nil
+}
+
+// deprecatedFormulae returns the installed formulae marked disabled or deprecated,
+// sorted by name. Disabled wins when both are set, since it is the later stage.
+func deprecatedFormulae(info brewInfo, filter nameFilter) []deprecatedFormula {
+	var found []deprecatedFormula
+	for _, f := range info.Formulae {
+		if !filter.keep(f.Name) {
+			continue
+		}
+		switch {
+		case f.Disabled:
+			found = append(found, deprecatedFormula{Name: f.Name, Status: "disabled", Reason: f.DisableReason, Date: f.DisableDate})
+		case f.Deprecated:
+			found = append(found, deprecatedFormula{Name: f.Name, Status: "deprecated", Reason: f.DeprecationReason, Date: f.DeprecationDate})
+		}
+	}
+	sort.Slice(found, func(i, j int) bool { return naturalLess(found[i].Name, found[j].Name) })
+	return found
+}
+
+// writeDeprecatedFormulae lists deprecated and disabled formulae with their reasons.
+func writeDeprecatedFormulae(w io.Writer, formulae []deprecatedFormula) error {
+	if _, err := fmt.Fprintln(w); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(w, "-- Deprecated / disabled formulae --"); err != nil {
+		return err
+	}
+	for _, f := range formulae {
+		line := fmt.Sprintf("%s [%s]", f.Name, f.Status)
+		if f.Reason != "" {
+			line += " " + strings.ReplaceAll(f.Reason, "_", " ")
+		}
+		if f.Date != "" {
+			line += " (since " + f.Date + ")"
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeDependencyGraph writes the formula dependency graph in Graphviz DOT form, one
+// node per formula and one edge per dependency. Render it with `dot -Tpng`.
+func writeDependencyGraph(path string, info brewInfo, filter nameFilter) error {
+	formulae := make([]brewFormula, 0, len(info.Formulae))
+	for _, f := range info.Formulae {
+		if filter.keep(f.Name) {
+			formulae = append(formulae, f)
+		}
+	}
+	sort.Slice(formulae, func(i, j int) bool { return naturalLess(formulae[i].Name, formulae[j].Name) })
+
+	var b strings.Builder
+	b.WriteString("digraph brew {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box, fontname=\"Helvetica\"];\n")
+	for _, f := range formulae {
+		fmt.Fprintf(&b, "  %q;\n", f.Name)
+	}
+	for _, f := range formulae {
+		deps := append([]string(nil), f.Dependencies...)
+		sort.Strings(deps)
+		for _, dep := range deps {
+			fmt.Fprintf(&b, "  %q -> %q;\n", f.Name, dep)
+		}
+	}
+	b.WriteString("}\n")
+	return os.WriteFile(path, []byte(b.String()), 0o644)
+}
+
+// caskAppTarget is where an installed cask placed one of its app bundles.
+type caskAppTarget struct {
+	Token string
+	Apps  []string
+}
+
+// caskAppTargets maps each installed cask to the app bundles its `app` artifacts install,
+// using `brew info --cask --installed --json=v2`. Relative targets live under appDir.
+func caskAppTargets(ctx context.Context, brew, appDir string) ([]caskAppTarget, error) {
+	var stderr bytes.Buffer
+	cmd := execCommand(ctx, brew, "info", "--cask", "--installed", "--json=v2")
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, wrapCommandErr("brew info --cask --installed --json=v2", err, strings.TrimSpace(stderr.String()))
+	}
+
+	var info brewInfo
+	if err := json.Unmarshal(out, &info); err != nil {
+		return nil, fmt.Errorf("decode brew cask JSON: %w", err)
+	}
+
+	targets := make([]caskAppTarget, 0, len(info.Casks))
+	for _, cask := range info.Casks {
+		target := caskAppTarget{Token: cask.Token}
+		for _, artifact := range cask.Artifacts {
+			var stanza map[string]json.RawMessage
+			if err := json.Unmarshal(artifact, &stanza); err != nil {
+				continue
+			}
+			raw, ok := stanza["app"]
+			if !ok {
+				continue
+			}
+			for _, app := range parseAppArtifact(raw) {
+				if !filepath.IsAbs(app) {
+					app = filepath.Join(appDir, app)
+				}
+				target.Apps = append(target.Apps, app)
+			}
+		}
+		targets = append(targets, target)
+	}
+	sort.Slice(targets, func(i, j int) bool { return naturalLess(targets[i].Token, targets[j].Token) })
+	return targets, nil
+}
+
+// parseAppArtifact reads an `app` stanza, which lists source bundles optionally followed
+// by a {"target": "..."} object that renames the preceding bundle.
+func parseAppArtifact(raw json.RawMessage) []string {
+	var elems []json.RawMessage
+	if err := json.Unmarshal(raw, &elems); err != nil {
+		return nil
+	}
+	var apps []string
+	for _, elem := range elems {
+		var name string
+		if err := json.Unmarshal(elem, &name); err == nil {
+			apps = append(apps, filepath.Base(name))
+			continue
+		}
+		var opts struct {
+			Target string `json:"target"`
+		}
+		if err := json.Unmarshal(elem, &opts); err == nil && opts.Target != "" && len(apps) > 0 {
+			apps[len(apps)-1] = opts.Target
+		}
+	}
+	return apps
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	arcer "github.com/yourorg/arc-sdk/errors"
+)
+
+// standardBrewPrefixes are the default install locations on Apple Silicon and Intel Macs.
+var standardBrewPrefixes = []string{"/opt/homebrew", "/usr/local"}
+
+// resolveBrew finds brew on PATH or, failing that, in a standard prefix: cron jobs and
+// LaunchAgents run with a minimal PATH that often lacks /opt/homebrew/bin. It returns
+// the absolute path, which the run passes to every brew invocation.
+func resolveBrew(hint string) (string, error) {
+	path, err := exec.LookPath("brew")
+	if err != nil {
+		for _, prefix := range standardBrewPrefixes {
+			candidate := filepath.Join(prefix, "bin", "brew")
+			if info, statErr := os.Stat(candidate); statErr == nil && !info.IsDir() && info.Mode()&0o111 != 0 {
+				path, err = candidate, nil
+				break
+			}
+		}
+	}
+	if err != nil {
+		return "", withExitCode(exitMissingCommand, &arcer.CLIError{
+			Msg:  fmt.Sprintf("brew is required but not found in PATH or under %s", strings.Join(standardBrewPrefixes, ", ")),
+			Hint: hint,
+			Suggestions: []string{
+				"which brew",
+				"echo $PATH",
+			},
+		})
+	}
+	if path, err = filepath.Abs(path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// detectBrewPrefixes returns every Homebrew prefix with a brew executable, starting
+// with active, or the one reported by `<brew> --prefix` when active is empty.
+func detectBrewPrefixes(ctx context.Context, brew, active string) []string {
+	var prefixes []string
+	seen := make(map[string]bool)
+	add := func(prefix string) {
+		if prefix == "" || seen[prefix] {
+			return
+		}
+		seen[prefix] = true
+		prefixes = append(prefixes, prefix)
+	}
+
+	if active == "" {
+		active, _ = brewPrefix(ctx, brew)
+	}
+	add(active)
+	for _, prefix := range standardBrewPrefixes {
+		if info, err := os.Stat(filepath.Join(prefix, "bin", "brew")); err == nil && !info.IsDir() {
+			add(prefix)
+		}
+	}
+	return prefixes
+}
+
+// writeBrewPrefixesSection lists the detected prefixes. When more than one exists, it
+// also lists each prefix's casks and formulae and records a warning about the duplicate
+// installation.
+func writeBrewPrefixesSection(r *exportRun) error {
+	prefixes := r.loadBrewPrefixes()
+	r.result.BrewPrefixes = prefixes
+	if err := writeLines(r.w, prefixes); err != nil {
+		return err
+	}
+	if len(prefixes) < 2 {
+		return nil
+	}
+
+	r.warn(fmt.Sprintf("multiple Homebrew installations detected (%s); duplicate casks or formulae may come from different prefixes", strings.Join(prefixes, ", ")))
+	for _, prefix := range prefixes {
+		brew := filepath.Join(prefix, "bin", "brew")
+		for _, kind := range []string{"cask", "formula"} {
+			if _, err := fmt.Fprintf(r.w, "\n-- %s (%s) --\n", prefix, kind); err != nil {
+				return err
+			}
+			lines, err := commandLines(r.ctx, brew, "list", "--"+kind, "--versions")
+			if err != nil {
+				r.warn(fmt.Sprintf("%s list --%s failed: %v", brew, kind, err))
+				continue
+			}
+			sortNatural(lines)
+			if err := writeLines(r.w, r.opts.nameFilter.apply(lines)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// brew returns the brew executable resolved for the run, or plain "brew" for runs that
+// never resolved one.
+func (r *exportRun) brew() string {
+	if r.brewBin == "" {
+		return "brew"
+	}
+	return r.brewBin
+}
+
+func (r *exportRun) loadBrewPrefixes() []string {
+	if r.brewPrefixes == nil {
+		if r.opts.noBrew {
+			r.brewPrefixes = []string{}
+		} else {
+			r.brewPrefixes = detectBrewPrefixes(r.ctx, r.brew(), r.activeBrewPrefix())
+		}
+	}
+	return r.brewPrefixes
+}
+
+// activeBrewPrefix returns the --brew-prefix/HOMEBREW_PREFIX override when it exists.
+// A missing override is recorded as a warning and `brew --prefix` is used instead.
+func (r *exportRun) activeBrewPrefix() string {
+	prefix := r.opts.brewPrefix
+	if prefix == "" {
+		return ""
+	}
+	if info, err := os.Stat(prefix); err != nil || !info.IsDir() {
+		r.warn(fmt.Sprintf("Homebrew prefix %s does not exist; falling back to `brew --prefix`", prefix))
+		return ""
+	}
+	return prefix
+}
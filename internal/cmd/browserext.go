@@ -0,0 +1,268 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// browserExtension is one extension installed in a browser profile.
+type browserExtension struct {
+	Browser string `json:"browser" yaml:"browser" toml:"browser" xml:"browser"`
+	Profile string `json:"profile,omitempty" yaml:"profile,omitempty" toml:"profile,omitempty" xml:"profile,omitempty"`
+	ID      string `json:"id" yaml:"id" toml:"id" xml:"id"`
+	Name    string `json:"name,omitempty" yaml:"name,omitempty" toml:"name,omitempty" xml:"name,omitempty"`
+	Version string `json:"version,omitempty" yaml:"version,omitempty" toml:"version,omitempty" xml:"version,omitempty"`
+}
+
+// chromiumBrowsers maps Chromium-based browsers to their data directory under
+// ~/Library/Application Support. Each profile keeps Extensions/<id>/<version>/.
+var chromiumBrowsers = []struct {
+	name string
+	dir  string
+}{
+	{"Chrome", "Google/Chrome"},
+	{"Chromium", "Chromium"},
+	{"Brave", "BraveSoftware/Brave-Browser"},
+	{"Edge", "Microsoft Edge"},
+	{"Vivaldi", "Vivaldi"},
+}
+
+// chromiumExtensions lists the extensions of every profile ("Default", "Profile 1", ...)
+// under dir. A missing browser yields no extensions and no error.
+func chromiumExtensions(browser, dir string) ([]browserExtension, error) {
+	profiles, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var found []browserExtension
+	for _, profile := range profiles {
+		extDir := filepath.Join(dir, profile.Name(), "Extensions")
+		ids, err := os.ReadDir(extDir)
+		if err != nil {
+			continue
+		}
+		for _, id := range ids {
+			if !id.IsDir() {
+				continue
+			}
+			ext := browserExtension{Browser: browser, Profile: profile.Name(), ID: id.Name()}
+			if versionDir := latestSubdir(filepath.Join(extDir, id.Name())); versionDir != "" {
+				ext.Name, ext.Version = readChromiumManifest(versionDir)
+			}
+			found = append(found, ext)
+		}
+	}
+	return found, nil
+}
+
+// latestSubdir returns the highest-versioned subdirectory of dir, in natural order so
+// 10.0 beats 9.0; Chromium keeps the previous version alongside the new one until the
+// next restart.
+func latestSubdir(dir string) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	sortNatural(names)
+	return filepath.Join(dir, names[len(names)-1])
+}
+
+// readChromiumManifest reads name and version from an extension's manifest.json,
+// resolving "__MSG_key__" names through the default locale's messages.json.
+func readChromiumManifest(dir string) (name, version string) {
+	data, err := os.ReadFile(filepath.Join(dir, "manifest.json"))
+	if err != nil {
+		return "", ""
+	}
+	var manifest struct {
+		Name          string `json:"name"`
+		Version       string `json:"version"`
+		DefaultLocale string `json:"default_locale"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return "", ""
+	}
+	name = manifest.Name
+	if key, ok := strings.CutPrefix(name, "__MSG_"); ok && manifest.DefaultLocale != "" {
+		key = strings.TrimSuffix(key, "__")
+		if msg := localizedMessage(filepath.Join(dir, "_locales", manifest.DefaultLocale, "messages.json"), key); msg != "" {
+			name = msg
+		}
+	}
+	return name, manifest.Version
+}
+
+// localizedMessage looks key up in a messages.json file. Keys are case-insensitive.
+func localizedMessage(path, key string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	var messages map[string]struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return ""
+	}
+	for k, m := range messages {
+		if strings.EqualFold(k, key) {
+			return m.Message
+		}
+	}
+	return ""
+}
+
+// firefoxExtensions reads extensions.json from every Firefox profile under dir,
+// skipping themes, language packs, and the add-ons bundled with Firefox itself. A
+// profile whose extensions.json can't be decoded is returned as a warning, and the
+// remaining profiles are still read.
+func firefoxExtensions(dir string) (found []browserExtension, warnings []string) {
+	manifests, _ := filepath.Glob(filepath.Join(dir, "Profiles", "*", "extensions.json"))
+	for _, path := range manifests {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var doc struct {
+			Addons []struct {
+				ID            string `json:"id"`
+				Version       string `json:"version"`
+				Type          string `json:"type"`
+				Location      string `json:"location"`
+				DefaultLocale struct {
+					Name string `json:"name"`
+				} `json:"defaultLocale"`
+			} `json:"addons"`
+		}
+		if err := json.Unmarshal(data, &doc); err != nil {
+			warnings = append(warnings, fmt.Sprintf("decode %s: %v", path, err))
+			continue
+		}
+		profile := filepath.Base(filepath.Dir(path))
+		for _, addon := range doc.Addons {
+			if addon.Type != "extension" || addon.Location == "app-builtin" || addon.Location == "app-system-defaults" {
+				continue
+			}
+			found = append(found, browserExtension{
+				Browser: "Firefox",
+				Profile: profile,
+				ID:      addon.ID,
+				Name:    addon.DefaultLocale.Name,
+				Version: addon.Version,
+			})
+		}
+	}
+	return found, warnings
+}
+
+// parsePluginkit reads `pluginkit -mAv -p com.apple.Safari.web-extension` lines such as
+// "+    com.1password.safari(8.10.0)\t<uuid>\t<date>\t<path>" into Safari extensions.
+func parsePluginkit(lines []string) []browserExtension {
+	var found []browserExtension
+	for _, line := range lines {
+		line = strings.TrimLeft(strings.TrimSpace(line), "+-!=? ")
+		id, _, _ := strings.Cut(line, "\t")
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		ext := browserExtension{Browser: "Safari", ID: id}
+		if open := strings.LastIndex(id, "("); open > 0 && strings.HasSuffix(id, ")") {
+			ext.ID, ext.Version = id[:open], id[open+1:len(id)-1]
+		}
+		found = append(found, ext)
+	}
+	return found
+}
+
+// browserExtensions gathers extensions from Safari, the Chromium family, and Firefox.
+// Browsers that are not installed contribute nothing; unreadable ones are warnings.
+func (r *exportRun) browserExtensions() ([]browserExtension, error) {
+	if r.homeDir == "" {
+		return nil, fmt.Errorf("home directory unknown")
+	}
+	support := filepath.Join(r.homeDir, "Library", "Application Support")
+
+	var found []browserExtension
+	if commandPresent("pluginkit")() {
+		lines, err := commandLines(r.ctx, "pluginkit", "-mAv", "-p", "com.apple.Safari.web-extension")
+		if err != nil {
+			r.warn(fmt.Sprintf("Safari extensions: %v", err))
+		} else {
+			found = append(found, parsePluginkit(lines)...)
+		}
+	}
+	for _, browser := range chromiumBrowsers {
+		exts, err := chromiumExtensions(browser.name, filepath.Join(support, browser.dir))
+		if err != nil {
+			r.warn(fmt.Sprintf("%s extensions: %v", browser.name, err))
+			continue
+		}
+		found = append(found, exts...)
+	}
+	exts, warnings := firefoxExtensions(filepath.Join(support, "Firefox"))
+	for _, warning := range warnings {
+		r.warn("Firefox extensions: " + warning)
+	}
+	found = append(found, exts...)
+
+	sort.SliceStable(found, func(i, j int) bool {
+		a, b := found[i], found[j]
+		if a.Browser != b.Browser {
+			return a.Browser < b.Browser
+		}
+		if a.Profile != b.Profile {
+			return a.Profile < b.Profile
+		}
+		return a.ID < b.ID
+	})
+	return found, nil
+}
+
+func writeBrowserExtensionsSection(r *exportRun) error {
+	exts, err := r.browserExtensions()
+	if err != nil {
+		return err
+	}
+	// --name-filter and --name-exclude apply to casks and formulae only.
+	r.stats.BrowserExtensionCount = len(exts)
+	r.result.BrowserExtensions = exts
+
+	for _, ext := range exts {
+		line := ext.Browser
+		if ext.Profile != "" {
+			line += " [" + ext.Profile + "]"
+		}
+		line += " " + ext.ID
+		if ext.Version != "" {
+			line += " " + ext.Version
+		}
+		if ext.Name != "" {
+			line += " (" + ext.Name + ")"
+		}
+		if _, err := fmt.Fprintln(r.w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLatestSubdir(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"9.0_0", "10.0_0", "9.5_0"} {
+		if err := os.Mkdir(filepath.Join(dir, name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := latestSubdir(dir), filepath.Join(dir, "10.0_0"); got != want {
+		t.Errorf("latestSubdir = %q; want %q", got, want)
+	}
+	if got := latestSubdir(filepath.Join(dir, "missing")); got != "" {
+		t.Errorf("latestSubdir(missing) = %q; want empty", got)
+	}
+}
+
+func TestFirefoxExtensionsSkipsBadProfile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(profile, data string) {
+		t.Helper()
+		path := filepath.Join(dir, "Profiles", profile, "extensions.json")
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("a.broken", `{"addons": [`)
+	write("b.default", `{"addons": [
+		{"id": "ublock@example", "version": "1.58.0", "type": "extension", "location": "app-profile", "defaultLocale": {"name": "uBlock"}},
+		{"id": "theme@example", "version": "1.0", "type": "theme", "location": "app-profile"},
+		{"id": "builtin@mozilla.org", "version": "1.0", "type": "extension", "location": "app-builtin"}
+	]}`)
+
+	found, warnings := firefoxExtensions(dir)
+	want := []browserExtension{{Browser: "Firefox", Profile: "b.default", ID: "ublock@example", Name: "uBlock", Version: "1.58.0"}}
+	if !reflect.DeepEqual(found, want) {
+		t.Errorf("found = %+v; want %+v", found, want)
+	}
+	if len(warnings) != 1 {
+		t.Errorf("warnings = %q; want one for the broken profile", warnings)
+	}
+}
+
+func TestParsePluginkit(t *testing.T) {
+	lines := []string{
+		"+    com.1password.safari(8.10.0)\tABC\t2024-01-01\t/Applications/1Password.app",
+		"     com.example.noversion\tDEF",
+		"",
+	}
+	want := []browserExtension{
+		{Browser: "Safari", ID: "com.1password.safari", Version: "8.10.0"},
+		{Browser: "Safari", ID: "com.example.noversion"},
+	}
+	if got := parsePluginkit(lines); !reflect.DeepEqual(got, want) {
+		t.Errorf("parsePluginkit = %+v; want %+v", got, want)
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	cachedBrewJSONName    = "brew_installed.json"
+	cachedFingerprintName = "brew_installed.fingerprint"
+)
+
+// packageFingerprint hashes the installed cask and formula lists (names and versions).
+// Any install, removal, or upgrade changes it.
+func packageFingerprint(casks, formulae []string) string {
+	h := sha256.New()
+	io.WriteString(h, "casks\n")
+	io.WriteString(h, strings.Join(casks, "\n"))
+	io.WriteString(h, "\nformulae\n")
+	io.WriteString(h, strings.Join(formulae, "\n"))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// restoreCachedBrewJSON copies the cached brew JSON to dest when its fingerprint matches.
+// It reports false without error when the cache is empty or stale.
+func restoreCachedBrewJSON(cacheDir, fingerprint, dest string) (bool, error) {
+	stored, err := os.ReadFile(filepath.Join(cacheDir, cachedFingerprintName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if strings.TrimSpace(string(stored)) != fingerprint {
+		return false, nil
+	}
+	if err := copyFile(filepath.Join(cacheDir, cachedBrewJSONName), dest); err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// storeCachedBrewJSON saves src and its fingerprint. The fingerprint is written last so
+// an interrupted store is treated as a cache miss.
+func storeCachedBrewJSON(cacheDir, fingerprint, src string) error {
+	if err := os.MkdirAll(cacheDir, 0o755); err != nil {
+		return err
+	}
+	fingerprintPath := filepath.Join(cacheDir, cachedFingerprintName)
+	if err := os.Remove(fingerprintPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := copyFile(src, filepath.Join(cacheDir, cachedBrewJSONName)); err != nil {
+		return err
+	}
+	return os.WriteFile(fingerprintPath, []byte(fingerprint+"\n"), 0o644)
+}
+
+func copyFile(src, dest string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	return writeFileAtomic(dest, in)
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	arcer "github.com/yourorg/arc-sdk/errors"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// enrichmentFlags are the export flags that add data to the result rather than
+// changing where or how it is written.
+var enrichmentFlags = []string{
+	"with-arch",
+	"with-sizes",
+	"with-metadata",
+	"with-quarantine",
+	"resolve-symlinks",
+	"follow-symlinks",
+	"min-version-age",
+	"include-dependencies-graph",
+	"manifest-with-apps",
+	"app-dir",
+	"collector",
+	"baseline",
+	"pinned",
+}
+
+type capabilitySection struct {
+	Name  string `json:"name"`
+	Title string `json:"title"`
+}
+
+type capabilityFlag struct {
+	Flag        string `json:"flag"`
+	Description string `json:"description"`
+}
+
+// capabilities describes what this build of arc-apps supports, for feature detection.
+type capabilities struct {
+	SchemaVersion string              `json:"schema_version"`
+	Formats       []string            `json:"formats"`
+	Sections      []capabilitySection `json:"sections"`
+	Enrichments   []capabilityFlag    `json:"enrichments"`
+	Profiles      []string            `json:"profiles"`
+}
+
+// buildCapabilities reads the tables the export command is built from; it runs no
+// external commands.
+func buildCapabilities() capabilities {
+	caps := capabilities{
+		SchemaVersion: exportSchemaVersion,
+		Formats: []string{
+			string(output.OutputTable), string(output.OutputJSON), string(output.OutputYAML),
+			string(output.OutputQuiet), formatTOML, formatXML, formatJSONL,
+		},
+		Profiles: profileNames(),
+	}
+	for _, name := range defaultSectionOrder {
+		caps.Sections = append(caps.Sections, capabilitySection{Name: name, Title: reportSections[name].title})
+	}
+	flags := exportCmd().Flags()
+	for _, name := range enrichmentFlags {
+		if flag := flags.Lookup(name); flag != nil {
+			_, usage := pflag.UnquoteUsage(flag)
+			caps.Enrichments = append(caps.Enrichments, capabilityFlag{Flag: "--" + name, Description: usage})
+		}
+	}
+	return caps
+}
+
+func writeCapabilities(w io.Writer, caps capabilities) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Schema version:\t%s\n", caps.SchemaVersion)
+	fmt.Fprintf(tw, "Output formats:\t%s\n", strings.Join(caps.Formats, ", "))
+	fmt.Fprintf(tw, "Profiles:\t%s\n", strings.Join(caps.Profiles, ", "))
+	fmt.Fprintln(tw, "\nSections (--sections):")
+	for _, section := range caps.Sections {
+		fmt.Fprintf(tw, "  %s\t%s\n", section.Name, section.Title)
+	}
+	fmt.Fprintln(tw, "\nEnrichments:")
+	for _, flag := range caps.Enrichments {
+		fmt.Fprintf(tw, "  %s\t%s\n", flag.Flag, flag.Description)
+	}
+	return tw.Flush()
+}
+
+func capabilitiesCmd() *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "capabilities",
+		Short: "List the output formats, report sections, and enrichment flags this build supports",
+		Long: strings.TrimSpace(`
+Print what this arc-apps build supports: output formats, report section names, export
+profiles, and the flags that add data to an export. Nothing is collected from the system,
+so scripts can call it cheaply to feature-detect before running an export.
+`),
+		Example: strings.TrimSpace(`
+Example:
+  # Check for a section before relying on it
+  arc-apps capabilities --format json | jq -e '.sections[] | select(.name == "pinned")'
+`),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			caps := buildCapabilities()
+			switch strings.ToLower(format) {
+			case "table":
+				return writeCapabilities(cmd.OutOrStdout(), caps)
+			case "json":
+				return jsonEncoder(cmd.OutOrStdout()).Encode(caps)
+			default:
+				return &arcer.CLIError{
+					Msg:         fmt.Sprintf("unknown --format %q", format),
+					Suggestions: []string{"table", "json"},
+				}
+			}
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", "table", "Output format: table or json")
+	return cmd
+}
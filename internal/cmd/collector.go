@@ -0,0 +1,98 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+
+	arcer "github.com/yourorg/arc-sdk/errors"
+)
+
+// collectorItem is one line of a --collector executable's NDJSON output:
+// {"type": "...", "name": "...", "version": "..."}. Only name is required.
+type collectorItem struct {
+	Collector string `json:"collector" yaml:"collector" toml:"collector" xml:"collector"`
+	Type      string `json:"type" yaml:"type" toml:"type" xml:"type"`
+	Name      string `json:"name" yaml:"name" toml:"name" xml:"name"`
+	Version   string `json:"version,omitempty" yaml:"version,omitempty" toml:"version,omitempty" xml:"version,omitempty"`
+}
+
+// resolveCollectors checks that every --collector path is an executable.
+func resolveCollectors(paths []string) ([]string, error) {
+	resolved := make([]string, 0, len(paths))
+	for _, p := range paths {
+		full, err := exec.LookPath(p)
+		if err != nil {
+			return nil, &arcer.CLIError{
+				Msg:  fmt.Sprintf("collector %s is not executable: %v", p, err),
+				Hint: "Pass the path to an executable that prints one JSON object per line: {\"type\",\"name\",\"version\"}.",
+			}
+		}
+		resolved = append(resolved, full)
+	}
+	return resolved, nil
+}
+
+// runCollector runs path and decodes its NDJSON output. Lines that are not valid items
+// are counted and skipped rather than failing the collector.
+func (r *exportRun) runCollector(path string) ([]collectorItem, error) {
+	name := filepath.Base(path)
+	var items []collectorItem
+	bad := 0
+	err := commandStream(r.ctx, func(line string) error {
+		var item collectorItem
+		if err := json.Unmarshal([]byte(line), &item); err != nil || item.Name == "" {
+			bad++
+			return nil
+		}
+		item.Collector = name
+		items = append(items, item)
+		return nil
+	}, path)
+	if err != nil {
+		return nil, wrapCommandErr("collector "+name, err, "Run the collector by hand to check its output.")
+	}
+	if bad > 0 {
+		r.warn(fmt.Sprintf("collector %s: skipped %d line(s) that were not {\"type\",\"name\",\"version\"} objects", name, bad))
+	}
+	return items, nil
+}
+
+func writeCollectorsSection(r *exportRun) error {
+	for i, path := range r.opts.collectors {
+		if i > 0 {
+			if _, err := fmt.Fprintln(r.w); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(r.w, "-- %s --\n", filepath.Base(path)); err != nil {
+			return err
+		}
+		items, err := r.runCollector(path)
+		if err != nil {
+			if !r.softFail(err) {
+				return err
+			}
+			continue
+		}
+		r.result.CollectorItems = append(r.result.CollectorItems, items...)
+		for _, item := range items {
+			kind := item.Type
+			if kind == "" {
+				kind = "item"
+			}
+			line := kind + " " + item.Name
+			if item.Version != "" {
+				line += " " + item.Version
+			}
+			if _, err := fmt.Fprintln(r.w, line); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// commandLog records every subprocess an export starts, in start order, for
+// CommandsRun. It is safe for the concurrent enrichment passes.
+type commandLog struct {
+	mu       sync.Mutex
+	commands []string
+}
+
+type commandLogKey struct{}
+
+// withCommandLog returns a context whose execCommand calls are recorded in log.
+func withCommandLog(ctx context.Context, log *commandLog) context.Context {
+	return context.WithValue(ctx, commandLogKey{}, log)
+}
+
+// execCommand is exec.CommandContext that also records the invocation in ctx's
+// commandLog, if any.
+func execCommand(ctx context.Context, name string, args ...string) *exec.Cmd {
+	if log, ok := ctx.Value(commandLogKey{}).(*commandLog); ok {
+		log.add(name, args)
+	}
+	return exec.CommandContext(ctx, name, args...)
+}
+
+func (l *commandLog) add(name string, args []string) {
+	line := make([]string, 0, len(args)+1)
+	line = append(line, shellQuote(name))
+	for _, arg := range args {
+		line = append(line, shellQuote(maskSecretArg(arg)))
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.commands = append(l.commands, strings.Join(line, " "))
+}
+
+// list returns a copy of the commands recorded so far.
+func (l *commandLog) list() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.commands...)
+}
+
+// maskSecretArg hides the value of a NAME=value or --name=value argument whose name
+// looks like a credential (the secretEnvMarkers used for HOMEBREW_* variables).
+func maskSecretArg(arg string) string {
+	name, _, ok := strings.Cut(arg, "=")
+	if !ok || strings.ContainsAny(name, " \n") {
+		return arg
+	}
+	upper := strings.ToUpper(name)
+	for _, marker := range secretEnvMarkers {
+		if strings.Contains(upper, marker) {
+			return name + "=(set)"
+		}
+	}
+	return arg
+}
+
+// shellQuote single-quotes s for sh when it contains anything but plain path and flag
+// characters, so a recorded command can be pasted into a shell as-is.
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789@%+=:,./_-") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
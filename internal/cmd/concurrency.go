@@ -0,0 +1,36 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import "sync"
+
+// parallelMap calls fn for every item on up to workers goroutines and returns the
+// results in input order, however the calls finish. Enrichment passes (arch, sizes,
+// quarantine, Spotlight metadata) use it so --concurrency speeds them up without
+// changing the report.
+func parallelMap[T, R any](workers int, items []T, fn func(T) R) []R {
+	results := make([]R, len(items))
+	if workers < 1 {
+		workers = 1
+	}
+	workers = min(workers, len(items))
+
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				results[i] = fn(items[i])
+			}
+		}()
+	}
+	for i := range items {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return results
+}
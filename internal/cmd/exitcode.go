@@ -0,0 +1,46 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"errors"
+)
+
+// Process exit codes. Scripts can branch on these; anything else that fails exits 1.
+const (
+	exitOK                = 0
+	exitFailure           = 1
+	exitUnsupportedOS     = 2
+	exitMissingCommand    = 3
+	exitWarningsAsFailure = 4
+)
+
+// exitCodeError tags an error with the process exit code it should produce. The
+// message is unchanged; the code is read by ExitCode.
+type exitCodeError struct {
+	code int
+	err  error
+}
+
+func (e *exitCodeError) Error() string { return e.err.Error() }
+func (e *exitCodeError) Unwrap() error { return e.err }
+
+func withExitCode(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &exitCodeError{code: code, err: err}
+}
+
+// ExitCode maps an error returned by the root command to the process exit code.
+func ExitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+	var coded *exitCodeError
+	if errors.As(err, &coded) {
+		return coded.code
+	}
+	return exitFailure
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	arcer "github.com/yourorg/arc-sdk/errors"
+)
+
+// nameFilter selects casks and formulae by name. A nil pattern matches everything.
+type nameFilter struct {
+	include *regexp.Regexp
+	exclude *regexp.Regexp
+}
+
+func newNameFilter(include, exclude string) (nameFilter, error) {
+	var f nameFilter
+	var err error
+	if f.include, err = compileNamePattern("--name-filter", include); err != nil {
+		return f, err
+	}
+	if f.exclude, err = compileNamePattern("--name-exclude", exclude); err != nil {
+		return f, err
+	}
+	return f, nil
+}
+
+// compileNamePattern treats patterns made only of literal characters and the *, ?
+// and [...] wildcards as anchored globs (openssl*, python@3.*); anything else is an
+// unanchored regular expression.
+func compileNamePattern(flag, pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	expr := pattern
+	if isGlobPattern(pattern) {
+		expr = globToRegexp(pattern)
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, &arcer.CLIError{
+			Msg:  fmt.Sprintf("invalid %s pattern %q: %v", flag, pattern, err),
+			Hint: "Use a glob such as 'openssl*' or a Go regular expression such as '^python@3\\.(11|12)$'.",
+		}
+	}
+	return re, nil
+}
+
+func isGlobPattern(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[") && !strings.ContainsAny(pattern, `^$()+{}|\`)
+}
+
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		case '[':
+			end := strings.IndexByte(glob[i:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return b.String()
+}
+
+func (f nameFilter) active() bool {
+	return f.include != nil || f.exclude != nil
+}
+
+func (f nameFilter) keep(name string) bool {
+	if f.include != nil && !f.include.MatchString(name) {
+		return false
+	}
+	if f.exclude != nil && f.exclude.MatchString(name) {
+		return false
+	}
+	return true
+}
+
+// apply filters `brew list --versions` lines by their package name.
+func (f nameFilter) apply(lines []string) []string {
+	if !f.active() {
+		return lines
+	}
+	kept := make([]string, 0, len(lines))
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) > 0 && f.keep(fields[0]) {
+			kept = append(kept, line)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type goBinary struct {
+	Name    string
+	Module  string
+	Version string
+}
+
+// goBinDir resolves where `go install` puts binaries: GOBIN, else the first GOPATH
+// entry's bin directory.
+func goBinDir(ctx context.Context) string {
+	lines, err := commandLines(ctx, "go", "env", "GOBIN", "GOPATH")
+	if err != nil {
+		return ""
+	}
+	// `go env` prints one line per variable; commandLines drops the line for an unset GOBIN.
+	if len(lines) == 2 {
+		return lines[0]
+	}
+	if len(lines) == 1 {
+		gopath := filepath.SplitList(lines[0])
+		if len(gopath) > 0 {
+			return filepath.Join(gopath[0], "bin")
+		}
+	}
+	return ""
+}
+
+// goBinaries runs `go version -m` over dir and returns each Go binary's main module.
+// Files that aren't Go binaries are ignored by go itself.
+func goBinaries(ctx context.Context, dir string) ([]goBinary, error) {
+	if _, err := os.Stat(dir); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	out, err := execCommand(ctx, "go", "version", "-m", dir).Output()
+	if err != nil {
+		return nil, wrapCommandErr("go version -m "+dir, err, "")
+	}
+	return parseGoVersionM(out), nil
+}
+
+func parseGoVersionM(out []byte) []goBinary {
+	var bins []goBinary
+	var cur *goBinary
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "\t") {
+			file, _, ok := strings.Cut(line, ": ")
+			if !ok {
+				continue
+			}
+			bins = append(bins, goBinary{Name: filepath.Base(file)})
+			cur = &bins[len(bins)-1]
+			continue
+		}
+		if cur == nil {
+			continue
+		}
+		fields := strings.Split(strings.TrimPrefix(line, "\t"), "\t")
+		switch {
+		case len(fields) >= 2 && fields[0] == "path" && cur.Module == "":
+			cur.Module = fields[1]
+		case len(fields) >= 3 && fields[0] == "mod":
+			cur.Module = fields[1]
+			cur.Version = fields[2]
+		}
+	}
+	sort.Slice(bins, func(i, j int) bool { return naturalLess(bins[i].Name, bins[j].Name) })
+	return bins
+}
+
+func writeGoBinariesSection(r *exportRun) error {
+	dir := goBinDir(r.ctx)
+	if dir == "" {
+		return nil
+	}
+	if _, err := fmt.Fprintf(r.w, "-- %s --\n", dir); err != nil {
+		return err
+	}
+	bins, err := goBinaries(r.ctx, dir)
+	if err != nil {
+		if r.softFail(err) {
+			return nil
+		}
+		return err
+	}
+	r.stats.GoBinaryCount = len(bins)
+	for _, bin := range bins {
+		line := bin.Name
+		if bin.Module != "" {
+			line = strings.TrimSpace(fmt.Sprintf("%s %s %s", bin.Name, bin.Module, bin.Version))
+		}
+		if _, err := fmt.Fprintln(r.w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	arcer "github.com/yourorg/arc-sdk/errors"
+	"github.com/yourorg/arc-sdk/utils"
+)
+
+// defaultSystemAppPrefixes are the directories --exclude-system-apps drops bundles
+// from unless --system-app-prefix replaces them: Apple's sealed system volume apps,
+// which can't be changed or removed anyway.
+var defaultSystemAppPrefixes = []string{"/System"}
+
+// systemAppPrefixes returns the cleaned prefixes to exclude, or nil when
+// --exclude-system-apps is off.
+func systemAppPrefixes(enabled bool, prefixes []string) []string {
+	if !enabled {
+		return nil
+	}
+	cleaned := make([]string, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		if prefix = strings.TrimSpace(prefix); prefix != "" {
+			cleaned = append(cleaned, filepath.Clean(utils.ExpandPath(prefix)))
+		}
+	}
+	return cleaned
+}
+
+// underAny reports whether path is one of dirs or inside one of them.
+func underAny(path string, dirs []string) bool {
+	for _, dir := range dirs {
+		if path == dir || strings.HasPrefix(path, strings.TrimSuffix(dir, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// ignoreRule is one gitignore-style line from an --exclude-file.
+type ignoreRule struct {
+	re     *regexp.Regexp
+	negate bool
+}
+
+// ignoreRules excludes app bundle paths. As in .gitignore, the last matching rule wins,
+// so a later !pattern re-includes paths an earlier pattern excluded.
+type ignoreRules []ignoreRule
+
+func (rules ignoreRules) ignored(path string) bool {
+	ignored := false
+	for _, rule := range rules {
+		if rule.re.MatchString(path) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+// loadIgnoreFiles reads every --exclude-file in order. Blank lines and # comments are
+// skipped; a pattern starting with ~ is expanded to the home directory.
+func loadIgnoreFiles(paths []string) (ignoreRules, error) {
+	var rules ignoreRules
+	for _, path := range paths {
+		path = utils.ExpandPath(path)
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, &arcer.CLIError{
+				Msg:  fmt.Sprintf("read --exclude-file: %v", err),
+				Hint: "Pass a file with one gitignore-style pattern per line.",
+			}
+		}
+		scanner := bufio.NewScanner(file)
+		for lineNo := 1; scanner.Scan(); lineNo++ {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			rule, err := compileIgnoreRule(line)
+			if err != nil {
+				file.Close()
+				return nil, &arcer.CLIError{
+					Msg:  fmt.Sprintf("%s:%d: invalid pattern %q: %v", path, lineNo, line, err),
+					Hint: "Patterns follow .gitignore syntax: *, **, ?, [abc], and a leading ! to re-include.",
+				}
+			}
+			rules = append(rules, rule)
+		}
+		err = scanner.Err()
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", path, err)
+		}
+	}
+	return rules, nil
+}
+
+// compileIgnoreRule converts a gitignore pattern into a regexp over absolute paths. A
+// pattern containing a slash is anchored at the filesystem root; one without matches
+// any path component. A match also covers everything beneath the matched directory.
+func compileIgnoreRule(pattern string) (ignoreRule, error) {
+	var rule ignoreRule
+	if strings.HasPrefix(pattern, "!") {
+		rule.negate = true
+		pattern = pattern[1:]
+	}
+	if strings.HasPrefix(pattern, "~") {
+		pattern = utils.ExpandPath(pattern)
+	}
+	pattern = strings.TrimSuffix(pattern, "/")
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	var b strings.Builder
+	if anchored {
+		b.WriteString("^/")
+	} else {
+		b.WriteString("(^|/)")
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(pattern[i:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := pattern[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			b.WriteString(regexp.QuoteMeta(string(pattern[i])))
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("(/.*)?$")
+
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return rule, err
+	}
+	rule.re = re
+	return rule, nil
+}
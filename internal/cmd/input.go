@@ -0,0 +1,51 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	arcer "github.com/yourorg/arc-sdk/errors"
+)
+
+// stdinPath is the file argument that makes verify and matrix read from stdin, e.g.
+// `ssh host cat brew.json | arc-apps verify -`.
+const stdinPath = "-"
+
+// openInput opens a subcommand's file argument, or returns stdin for "-". Closing the
+// returned reader never closes stdin.
+func openInput(path string, stdin io.Reader) (io.ReadCloser, error) {
+	if path == stdinPath {
+		return io.NopCloser(stdin), nil
+	}
+	return os.Open(path)
+}
+
+// inputName labels a file argument in messages.
+func inputName(path string) string {
+	if path == stdinPath {
+		return "stdin"
+	}
+	return path
+}
+
+// checkSingleStdin rejects more than one "-" argument: stdin can only be read once.
+func checkSingleStdin(paths []string) error {
+	seen := false
+	for _, path := range paths {
+		if path != stdinPath {
+			continue
+		}
+		if seen {
+			return &arcer.CLIError{
+				Msg:  fmt.Sprintf("%q given more than once", stdinPath),
+				Hint: "Only one input can come from stdin; pass the others as files.",
+			}
+		}
+		seen = true
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// sectionLine is one line of --output jsonl. A line is emitted as each report section
+// finishes; the final line carries the full result under section "result".
+type sectionLine struct {
+	Section string        `json:"section"`
+	Items   any           `json:"items,omitempty"`
+	Result  *exportResult `json:"result,omitempty"`
+}
+
+// sectionEmitter receives each finished section's items during runExport.
+type sectionEmitter func(section string, items any) error
+
+// newJSONLEmitter writes section lines to w, passing each through rewrite (used for
+// --redact-home) before it is written.
+func newJSONLEmitter(w io.Writer, rewrite func(string) string) sectionEmitter {
+	return func(section string, items any) error {
+		return writeJSONLine(w, sectionLine{Section: section, Items: items}, rewrite)
+	}
+}
+
+func writeJSONLine(w io.Writer, line sectionLine, rewrite func(string) string) error {
+	data, err := json.Marshal(line)
+	if err != nil {
+		return err
+	}
+	text := string(data)
+	if rewrite != nil {
+		text = rewrite(text)
+	}
+	_, err = io.WriteString(w, text+"\n")
+	return err
+}
+
+// sectionCapture tees a section's report text so sections without structured items can
+// still be emitted, as their non-blank lines.
+type sectionCapture struct {
+	w   io.Writer
+	buf bytes.Buffer
+}
+
+func (c *sectionCapture) Write(p []byte) (int, error) {
+	c.buf.Write(p)
+	return c.w.Write(p)
+}
+
+func (c *sectionCapture) lines() []string {
+	lines := []string{}
+	for _, line := range strings.Split(c.buf.String(), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
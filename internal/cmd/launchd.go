@@ -0,0 +1,86 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+type launchdDir struct {
+	label string
+	path  string
+}
+
+func launchdDirs(homeDir string) []launchdDir {
+	return []launchdDir{
+		{label: "~/Library/LaunchAgents", path: filepath.Join(homeDir, "Library", "LaunchAgents")},
+		{label: "/Library/LaunchAgents", path: "/Library/LaunchAgents"},
+		{label: "/Library/LaunchDaemons", path: "/Library/LaunchDaemons"},
+	}
+}
+
+// launchdProgram holds the keys of a launchd plist that identify what it runs.
+type launchdProgram struct {
+	Program          string   `json:"Program"`
+	ProgramArguments []string `json:"ProgramArguments"`
+}
+
+// writeLaunchdSection lists plist files in each launchd directory and returns how many
+// were found. Missing directories are skipped. When verbose is set, each plist is
+// converted with plutil so its program can be shown alongside the file name.
+func writeLaunchdSection(ctx context.Context, w io.Writer, homeDir string, verbose bool) (int, error) {
+	count := 0
+	for i, dir := range launchdDirs(homeDir) {
+		if i > 0 {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return count, err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "-- %s ---\n", dir.label); err != nil {
+			return count, err
+		}
+		names, err := listDirSorted(dir.path)
+		if err != nil {
+			continue
+		}
+		for _, name := range names {
+			if !strings.HasSuffix(name, ".plist") {
+				continue
+			}
+			count++
+			line := name
+			if verbose {
+				line = fmt.Sprintf("%s -> %s", name, resolveLaunchdProgram(ctx, filepath.Join(dir.path, name)))
+			}
+			if _, err := fmt.Fprintln(w, line); err != nil {
+				return count, err
+			}
+		}
+	}
+	return count, nil
+}
+
+func resolveLaunchdProgram(ctx context.Context, path string) string {
+	out, err := execCommand(ctx, "plutil", "-convert", "json", "-o", "-", path).Output()
+	if err != nil {
+		return "(unreadable)"
+	}
+	var prog launchdProgram
+	if err := json.Unmarshal(out, &prog); err != nil {
+		return "(unreadable)"
+	}
+	switch {
+	case len(prog.ProgramArguments) > 0:
+		return strings.Join(prog.ProgramArguments, " ")
+	case prog.Program != "":
+		return prog.Program
+	default:
+		return "(no program)"
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"strings"
+)
+
+// loginItemsScript prints one login item name per line. osascript's default list
+// output separates items with ", ", which splits names that contain a comma.
+const loginItemsScript = `set AppleScript's text item delimiters to linefeed
+tell application "System Events" to set itemNames to the name of every login item
+return itemNames as text`
+
+// loginItems asks System Events for the user's login items. When the terminal has not
+// been granted Automation access, the items are skipped and a warning is returned instead.
+func loginItems(ctx context.Context) ([]string, string, error) {
+	cmd := execCommand(ctx, "osascript", "-e", loginItemsScript)
+	out, err := cmd.CombinedOutput()
+	text := strings.TrimSpace(string(out))
+	if err != nil {
+		if isAutomationDenied(text) {
+			return nil, "login items skipped: osascript is not authorized to control System Events (grant access under System Settings > Privacy & Security > Automation)", nil
+		}
+		return nil, "", wrapCommandErr("osascript (login items)", err, text)
+	}
+	return parseLoginItems(text), "", nil
+}
+
+// parseLoginItems splits loginItemsScript output into sorted names, one per line.
+func parseLoginItems(text string) []string {
+	items := []string{}
+	for _, line := range strings.Split(text, "\n") {
+		if name := strings.TrimSpace(line); name != "" {
+			items = append(items, name)
+		}
+	}
+	sortNatural(items)
+	return items
+}
+
+func isAutomationDenied(output string) bool {
+	lower := strings.ToLower(output)
+	return strings.Contains(output, "-1743") || strings.Contains(lower, "not authorized") || strings.Contains(lower, "not allowed assistive access")
+}
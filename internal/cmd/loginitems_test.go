@@ -0,0 +1,43 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIsAutomationDenied(t *testing.T) {
+	tests := []struct {
+		output string
+		want   bool
+	}{
+		{"execution error: Not authorized to send Apple events to System Events. (-1743)", true},
+		{"System Events got an error: osascript is not allowed assistive access.", true},
+		{"execution error: Not Authorized to send Apple events", true},
+		{"syntax error: Expected end of line", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := isAutomationDenied(tt.output); got != tt.want {
+			t.Errorf("isAutomationDenied(%q) = %v; want %v", tt.output, got, tt.want)
+		}
+	}
+}
+
+func TestParseLoginItems(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"", []string{}},
+		{"Rectangle\nDropbox", []string{"Dropbox", "Rectangle"}},
+		{"Backup, Sync & Share\nAlfred 5\n", []string{"Alfred 5", "Backup, Sync & Share"}},
+	}
+	for _, tt := range tests {
+		if got := parseLoginItems(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseLoginItems(%q) = %q; want %q", tt.text, got, tt.want)
+		}
+	}
+}
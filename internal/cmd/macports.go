@@ -0,0 +1,47 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import "strings"
+
+// parsePortInstalled converts `port installed` output ("  curl @8.4.0_0 (active)")
+// into `brew list --versions` style lines ("curl 8.4.0_0"), one per port, so the brew
+// helpers apply unchanged. Inactive versions are kept after the active one.
+func parsePortInstalled(lines []string) []string {
+	versions := make(map[string][]string)
+	var names []string
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || !strings.HasPrefix(fields[1], "@") {
+			continue
+		}
+		name, version := fields[0], strings.TrimPrefix(fields[1], "@")
+		if _, ok := versions[name]; !ok {
+			names = append(names, name)
+		}
+		if len(fields) > 2 && fields[2] == "(active)" {
+			versions[name] = append([]string{version}, versions[name]...)
+		} else {
+			versions[name] = append(versions[name], version)
+		}
+	}
+	sortNatural(names)
+
+	ports := make([]string, 0, len(names))
+	for _, name := range names {
+		ports = append(ports, name+" "+strings.Join(versions[name], " "))
+	}
+	return ports
+}
+
+func writeMacPortsSection(r *exportRun) error {
+	lines, err := commandLines(r.ctx, "port", "installed")
+	if err != nil {
+		return wrapCommandErr("port installed", err, "Run `sudo port selfupdate` or remove MacPorts from PATH.")
+	}
+	ports := r.opts.nameFilter.apply(parsePortInstalled(lines))
+	r.stats.MacPortsCount = len(ports)
+	r.result.MacPorts = packageItems(ports)
+	return writeLines(r.w, ports)
+}
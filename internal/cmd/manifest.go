@@ -0,0 +1,60 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// manifestEntries normalizes `brew list --versions` lines into "kind\tname\tversions"
+// entries with lowercase names and sorted versions.
+func manifestEntries(kind string, lines []string) []string {
+	items := packageItems(lines)
+	entries := make([]string, 0, len(items))
+	for _, item := range items {
+		entries = append(entries, kind+"\t"+strings.ToLower(item.Name)+"\t"+strings.Join(item.sortedVersions(), " "))
+	}
+	return entries
+}
+
+// manifestHash hashes the installed casks and formulae (and, when apps is non-nil, app
+// bundle names) so machines with identical software produce identical hashes
+// regardless of paths or timestamps.
+func manifestHash(casks, formulae, apps []string) string {
+	entries := append(manifestEntries("cask", casks), manifestEntries("formula", formulae)...)
+	for _, app := range apps {
+		entries = append(entries, "app\t"+strings.ToLower(filepath.Base(app))+"\t")
+	}
+	sort.Strings(entries)
+
+	h := sha256.New()
+	for _, entry := range entries {
+		h.Write([]byte(entry))
+		h.Write([]byte{'\n'})
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil))
+}
+
+// computeManifestHash uses the same filtered lists the report shows.
+func (r *exportRun) computeManifestHash() (string, error) {
+	casks, err := r.loadCasks()
+	if err != nil {
+		return "", err
+	}
+	formulae, err := r.loadFormulae()
+	if err != nil {
+		return "", err
+	}
+	var apps []string
+	if r.opts.manifestApps {
+		if apps, err = r.loadAppBundles(); err != nil {
+			return "", err
+		}
+	}
+	return manifestHash(casks, formulae, apps), nil
+}
@@ -0,0 +1,308 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	arcer "github.com/yourorg/arc-sdk/errors"
+	"github.com/yourorg/arc-sdk/utils"
+)
+
+const (
+	matrixTable = "table"
+	matrixCSV   = "csv"
+	matrixJSON  = "json"
+)
+
+// matrixSources maps --source names to the row sources they select, in matrix order.
+var matrixSources = []struct{ flag, source string }{
+	{"apps", "app"},
+	{"casks", "cask"},
+	{"formulae", "formula"},
+	{"macports", "macports"},
+	{"nix", "nix"},
+}
+
+// parseMatrixSources reads a --source list such as "casks,formulae". An empty list
+// selects every source, reported as nil.
+func parseMatrixSources(raw string) (map[string]bool, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	names := make([]string, 0, len(matrixSources))
+	for _, s := range matrixSources {
+		names = append(names, s.flag)
+	}
+	selected := make(map[string]bool)
+	for _, part := range strings.Split(raw, ",") {
+		name := strings.ToLower(strings.TrimSpace(part))
+		if name == "" {
+			continue
+		}
+		found := false
+		for _, s := range matrixSources {
+			if s.flag == name {
+				selected[s.source] = true
+				found = true
+			}
+		}
+		if !found {
+			return nil, &arcer.CLIError{
+				Msg:         fmt.Sprintf("unknown --source %q", name),
+				Hint:        "Valid sources: " + strings.Join(names, ", "),
+				Suggestions: []string{"arc-apps matrix --source casks,formulae a.json b.json"},
+			}
+		}
+	}
+	if len(selected) == 0 {
+		return nil, &arcer.CLIError{
+			Msg:  "--source must name at least one source",
+			Hint: "Valid sources: " + strings.Join(names, ", "),
+		}
+	}
+	return selected, nil
+}
+
+// matrixRow is one package and the version each host has installed. A host missing
+// from Versions does not have the package.
+type matrixRow struct {
+	Source   string            `json:"source"`
+	Name     string            `json:"name"`
+	Versions map[string]string `json:"versions"`
+}
+
+// hostMatrix is the package-by-host presence matrix built from several exports.
+type hostMatrix struct {
+	Hosts []string    `json:"hosts"`
+	Rows  []matrixRow `json:"packages"`
+}
+
+func matrixCmd() *cobra.Command {
+	var (
+		format string
+		source string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "matrix <export.json|->...",
+		Short: "Show which packages are installed on which hosts",
+		Long: strings.TrimSpace(`
+Read JSON results written by 'arc-apps export --output json' on several machines and print
+a package-by-host matrix of apps, casks, formulae, MacPorts ports, and Nix packages. Each
+cell holds the installed version, or "-" when the host does not have the package. One
+argument may be - to read that export from stdin.
+`),
+		Example: strings.TrimSpace(`
+Example:
+  # Compare the team's laptops
+  arc-apps matrix alice.json bob.json carol.json
+
+  # Spreadsheet-friendly output
+  arc-apps matrix --format csv exports/*.json > matrix.csv
+
+  # Only compare command-line packages
+  arc-apps matrix --source formulae alice.json bob.json
+
+  # Compare a remote machine with a local export
+  ssh build-01 arc-apps export --output json | arc-apps matrix - local.json
+`),
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format = strings.ToLower(format)
+			switch format {
+			case matrixTable, matrixCSV, matrixJSON:
+			default:
+				return &arcer.CLIError{
+					Msg:         fmt.Sprintf("unknown --format %q", format),
+					Suggestions: []string{matrixTable, matrixCSV, matrixJSON},
+				}
+			}
+
+			sources, err := parseMatrixSources(source)
+			if err != nil {
+				return err
+			}
+			if err := checkSingleStdin(args); err != nil {
+				return err
+			}
+			results := make([]exportResult, 0, len(args))
+			hosts := make([]string, 0, len(args))
+			for _, arg := range args {
+				path := utils.ExpandPath(arg)
+				result, err := readExportResult(path, cmd.InOrStdin())
+				if err != nil {
+					return err
+				}
+				results = append(results, result)
+				hosts = append(hosts, matrixHostName(result, path, hosts))
+			}
+
+			matrix := buildHostMatrix(hosts, results, sources)
+			w := cmd.OutOrStdout()
+			switch format {
+			case matrixCSV:
+				return writeMatrixCSV(w, matrix)
+			case matrixJSON:
+				return jsonEncoder(w).Encode(matrix)
+			default:
+				return writeMatrixTable(w, matrix)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", matrixTable, "Matrix output: table, csv, or json")
+	cmd.Flags().StringVar(&source, "source", "", "Only compare these sources: comma-separated apps, casks, formulae, macports, nix (default all)")
+	return cmd
+}
+
+// readExportResult decodes a JSON result written by `arc-apps export --output json`,
+// from stdin when path is "-".
+func readExportResult(path string, stdin io.Reader) (exportResult, error) {
+	var result exportResult
+	file, err := openInput(path, stdin)
+	if err != nil {
+		return result, &arcer.CLIError{
+			Msg:  fmt.Sprintf("read export: %v", err),
+			Hint: "Create one with `arc-apps export --output json > host.json`.",
+		}
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return result, &arcer.CLIError{
+			Msg:  fmt.Sprintf("read export: %v", err),
+			Hint: "Create one with `arc-apps export --output json > host.json`.",
+		}
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return result, &arcer.CLIError{
+			Msg:  fmt.Sprintf("%s is not an export result: %v", inputName(path), err),
+			Hint: "Pass files written by `arc-apps export --output json`.",
+		}
+	}
+	return result, nil
+}
+
+// matrixHostName labels a result's column with its hostname, falling back to the file
+// name, and numbers repeats so two exports from one host stay distinct.
+func matrixHostName(result exportResult, path string, taken []string) string {
+	name := result.Hostname
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(inputName(path)), filepath.Ext(path))
+	}
+	used := make(map[string]bool, len(taken))
+	for _, host := range taken {
+		used[host] = true
+	}
+	candidate := name
+	for n := 2; used[candidate]; n++ {
+		candidate = fmt.Sprintf("%s#%d", name, n)
+	}
+	return candidate
+}
+
+// buildHostMatrix merges the item lists of results into rows sorted by source, then name.
+// When sources is non-nil, only those sources get rows.
+func buildHostMatrix(hosts []string, results []exportResult, sources map[string]bool) hostMatrix {
+	rows := make(map[string]*matrixRow)
+	add := func(host, source, name, version string) {
+		if sources != nil && !sources[source] {
+			return
+		}
+		key := source + "\x00" + name
+		row, ok := rows[key]
+		if !ok {
+			row = &matrixRow{Source: source, Name: name, Versions: make(map[string]string)}
+			rows[key] = row
+		}
+		row.Versions[host] = version
+	}
+
+	for i, result := range results {
+		host := hosts[i]
+		for _, app := range result.Apps {
+			add(host, "app", app, "")
+		}
+		for _, item := range result.Casks {
+			add(host, "cask", item.Name, item.version())
+		}
+		for _, item := range result.Formulae {
+			add(host, "formula", item.Name, item.version())
+		}
+		for _, item := range result.MacPorts {
+			add(host, "macports", item.Name, item.version())
+		}
+		for _, item := range result.NixPackages {
+			add(host, "nix", item.Name, item.version())
+		}
+	}
+
+	matrix := hostMatrix{Hosts: hosts, Rows: make([]matrixRow, 0, len(rows))}
+	for _, row := range rows {
+		matrix.Rows = append(matrix.Rows, *row)
+	}
+	sort.Slice(matrix.Rows, func(i, j int) bool {
+		a, b := matrix.Rows[i], matrix.Rows[j]
+		if a.Source != b.Source {
+			return a.Source < b.Source
+		}
+		return a.Name < b.Name
+	})
+	return matrix
+}
+
+// cell renders one host's entry: the version, "yes" when installed without a version
+// (apps), or "-" when absent.
+func (row matrixRow) cell(host string) string {
+	version, ok := row.Versions[host]
+	switch {
+	case !ok:
+		return "-"
+	case version == "":
+		return "yes"
+	default:
+		return version
+	}
+}
+
+func writeMatrixTable(w io.Writer, matrix hostMatrix) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "SOURCE\tPACKAGE\t%s\n", strings.Join(matrix.Hosts, "\t"))
+	for _, row := range matrix.Rows {
+		cells := make([]string, len(matrix.Hosts))
+		for i, host := range matrix.Hosts {
+			cells[i] = row.cell(host)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", row.Source, row.Name, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
+
+func writeMatrixCSV(w io.Writer, matrix hostMatrix) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(append([]string{"source", "package"}, matrix.Hosts...)); err != nil {
+		return err
+	}
+	for _, row := range matrix.Rows {
+		record := []string{row.Source, row.Name}
+		for _, host := range matrix.Hosts {
+			record = append(record, row.cell(host))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// mdlsBatchSize caps how many bundles one mdls call reads, keeping argv well under
+// ARG_MAX on machines with thousands of apps.
+const mdlsBatchSize = 200
+
+// appMetadata is what Spotlight records about an app bundle (--with-metadata).
+type appMetadata struct {
+	Path    string   `json:"path" yaml:"path" toml:"path" xml:"path"`
+	Version string   `json:"version,omitempty" yaml:"version,omitempty" toml:"version,omitempty" xml:"version,omitempty"`
+	Kind    string   `json:"kind,omitempty" yaml:"kind,omitempty" toml:"kind,omitempty" xml:"kind,omitempty"`
+	Tags    []string `json:"tags,omitempty" yaml:"tags,omitempty" toml:"tags,omitempty" xml:"tags>tag,omitempty"`
+}
+
+// mdlsValues reads one attribute for every path with a single `mdls -raw` call. Values
+// come back NUL-separated in argument order; unset attributes are empty.
+func mdlsValues(ctx context.Context, attr string, paths []string) ([]string, error) {
+	args := append([]string{"-raw", "-nullMarker", "", "-name", attr}, paths...)
+	var stderr bytes.Buffer
+	cmd := execCommand(ctx, "mdls", args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, wrapCommandErr("mdls -name "+attr, err, strings.TrimSpace(stderr.String()))
+	}
+	values := strings.Split(string(out), "\x00")
+	if len(values) == len(paths)+1 && values[len(values)-1] == "" {
+		values = values[:len(paths)]
+	}
+	if len(values) != len(paths) {
+		return nil, fmt.Errorf("mdls -name %s: got %d values for %d bundles", attr, len(values), len(paths))
+	}
+	return values, nil
+}
+
+// parseMdlsArray reads an array value as `mdls -raw` prints it:
+// "(\n    Red,\n    \"Needs review\"\n)".
+func parseMdlsArray(raw string) []string {
+	raw = strings.TrimSpace(raw)
+	if !strings.HasPrefix(raw, "(") || !strings.HasSuffix(raw, ")") {
+		return nil
+	}
+	var items []string
+	for _, line := range strings.Split(raw[1:len(raw)-1], "\n") {
+		item := strings.Trim(strings.TrimSpace(line), ",")
+		item = strings.Trim(strings.TrimSpace(item), `"`)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// appMetadataFor reads version, kind, and Finder tags for bundles from the Spotlight
+// index, three batched mdls calls per mdlsBatchSize bundles, with up to workers
+// batches in flight.
+func appMetadataFor(ctx context.Context, bundles []string, workers int) ([]appMetadata, error) {
+	var batches [][]string
+	for start := 0; start < len(bundles); start += mdlsBatchSize {
+		batches = append(batches, bundles[start:min(start+mdlsBatchSize, len(bundles))])
+	}
+	type batchResult struct {
+		meta []appMetadata
+		err  error
+	}
+	results := parallelMap(workers, batches, func(batch []string) batchResult {
+		meta, err := batchMetadata(ctx, batch)
+		return batchResult{meta, err}
+	})
+	meta := make([]appMetadata, 0, len(bundles))
+	for _, result := range results {
+		if result.err != nil {
+			return nil, result.err
+		}
+		meta = append(meta, result.meta...)
+	}
+	return meta, nil
+}
+
+func batchMetadata(ctx context.Context, batch []string) ([]appMetadata, error) {
+	versions, err := mdlsValues(ctx, "kMDItemVersion", batch)
+	if err != nil {
+		return nil, err
+	}
+	kinds, err := mdlsValues(ctx, "kMDItemKind", batch)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := mdlsValues(ctx, "kMDItemUserTags", batch)
+	if err != nil {
+		return nil, err
+	}
+	meta := make([]appMetadata, 0, len(batch))
+	for i, bundle := range batch {
+		meta = append(meta, appMetadata{
+			Path:    bundle,
+			Version: strings.TrimSpace(versions[i]),
+			Kind:    strings.TrimSpace(kinds[i]),
+			Tags:    parseMdlsArray(tags[i]),
+		})
+	}
+	return meta, nil
+}
+
+// metadataLine renders an app entry with its Spotlight details folded in, e.g.
+// "/Applications/Zed.app 0.150.4 (Application) [Work]".
+func metadataLine(path string, meta appMetadata) string {
+	line := path
+	if meta.Version != "" {
+		line += " " + meta.Version
+	}
+	if meta.Kind != "" {
+		line += " (" + meta.Kind + ")"
+	}
+	if len(meta.Tags) > 0 {
+		line += " [" + strings.Join(meta.Tags, ", ") + "]"
+	}
+	return line
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMdlsArray(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want []string
+	}{
+		{"(\n    Red,\n    \"Needs review\"\n)", []string{"Red", "Needs review"}},
+		{"(\n)", nil},
+		{"", nil},
+		{"Application", nil},
+	}
+	for _, tt := range tests {
+		if got := parseMdlsArray(tt.raw); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseMdlsArray(%q) = %q; want %q", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestMetadataLine(t *testing.T) {
+	tests := []struct {
+		meta appMetadata
+		want string
+	}{
+		{appMetadata{Version: "0.150.4", Kind: "Application", Tags: []string{"Work", "Red"}}, "/Applications/Zed.app 0.150.4 (Application) [Work, Red]"},
+		{appMetadata{Kind: "Application"}, "/Applications/Zed.app (Application)"},
+		{appMetadata{}, "/Applications/Zed.app"},
+	}
+	for _, tt := range tests {
+		if got := metadataLine("/Applications/Zed.app", tt.meta); got != tt.want {
+			t.Errorf("metadataLine(%+v) = %q; want %q", tt.meta, got, tt.want)
+		}
+	}
+}
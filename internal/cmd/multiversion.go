@@ -0,0 +1,91 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+)
+
+// multiVersionFormula is a formula with older versions still in the Cellar, which
+// `brew cleanup` would remove. ReclaimableBytes, set with --with-sizes, is the size of
+// every version but Latest.
+type multiVersionFormula struct {
+	Name             string   `json:"name" yaml:"name" toml:"name" xml:"name"`
+	Versions         []string `json:"versions" yaml:"versions" toml:"versions" xml:"versions>version"`
+	Latest           string   `json:"latest" yaml:"latest" toml:"latest" xml:"latest"`
+	ReclaimableBytes int64    `json:"reclaimable_bytes,omitempty" yaml:"reclaimable_bytes,omitempty" toml:"reclaimable_bytes,omitempty" xml:"reclaimable_bytes,omitempty"`
+}
+
+// multiVersionFormulae returns the items with more than one installed version, in
+// their listed order.
+func multiVersionFormulae(items []packageItem) []multiVersionFormula {
+	var multi []multiVersionFormula
+	for _, item := range items {
+		if len(item.Versions) > 1 {
+			multi = append(multi, multiVersionFormula{Name: item.Name, Versions: item.Versions, Latest: highestVersion(item.Versions)})
+		}
+	}
+	return multi
+}
+
+// oldVersionSize sums <cellar>/<name>/<version> for every version but the latest.
+func oldVersionSize(cellar string, f multiVersionFormula) int64 {
+	var total int64
+	for _, version := range f.Versions {
+		if version != f.Latest {
+			total += bundleSize(filepath.Join(cellar, f.Name, version))
+		}
+	}
+	return total
+}
+
+// writeMultiVersionSection lists formulae with several versions in the Cellar. With
+// --with-sizes, the old versions are measured under <prefix>/Cellar, up to
+// --concurrency at a time, for a total of what `brew cleanup` would reclaim.
+func writeMultiVersionSection(r *exportRun) error {
+	formulae, err := r.loadFormulae()
+	if err != nil {
+		return err
+	}
+	multi := multiVersionFormulae(packageItems(formulae))
+	r.stats.MultiVersionFormulaCount = len(multi)
+
+	var cellar string
+	if r.opts.withSizes && len(multi) > 0 {
+		if prefixes := r.loadBrewPrefixes(); len(prefixes) > 0 {
+			cellar = filepath.Join(prefixes[0], "Cellar")
+		}
+	}
+	if cellar != "" {
+		sizes := parallelMap(r.opts.concurrency, multi, func(f multiVersionFormula) int64 { return oldVersionSize(cellar, f) })
+		for i := range multi {
+			multi[i].ReclaimableBytes = sizes[i]
+			r.result.ReclaimableBytes += sizes[i]
+		}
+	}
+	r.result.MultiVersionFormulae = multi
+
+	lines := make([]string, 0, len(multi)+2)
+	for _, f := range multi {
+		line := fmt.Sprintf("%s: %s (latest %s)", f.Name, strings.Join(f.Versions, ", "), f.Latest)
+		if cellar != "" {
+			line += ", " + humanize.Bytes(uint64(f.ReclaimableBytes)) + " reclaimable"
+		}
+		lines = append(lines, line)
+	}
+	summary := fmt.Sprintf("(%d formulae with old versions", len(multi))
+	if cellar != "" {
+		summary += fmt.Sprintf("; %s reclaimable", humanize.Bytes(uint64(r.result.ReclaimableBytes)))
+	}
+	summary += ")"
+	if len(multi) > 0 {
+		summary += "; run `brew cleanup` to remove them"
+	}
+	lines = append(lines, summary)
+	return writeLines(r.w, lines)
+}
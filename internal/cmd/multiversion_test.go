@@ -0,0 +1,57 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMultiVersionFormulae(t *testing.T) {
+	multi := multiVersionFormulae(packageItems([]string{"jq 1.6 1.7.1", "wget 1.24.5"}))
+	if len(multi) != 1 {
+		t.Fatalf("got %d multi-version formulae, want 1: %+v", len(multi), multi)
+	}
+	if multi[0].Name != "jq" || multi[0].Latest != "1.7.1" {
+		t.Errorf("got %+v; want jq with latest 1.7.1", multi[0])
+	}
+
+	cellar := t.TempDir()
+	writeKegs(t, cellar, "jq", map[string]int{"1.6": 40, "1.7.1": 9})
+	if got := oldVersionSize(cellar, multi[0]); got != 40 {
+		t.Errorf("oldVersionSize = %d; want 40", got)
+	}
+}
+
+// writeKegs creates <cellar>/<name>/<version>/lib files of the given sizes.
+func writeKegs(t *testing.T, cellar, name string, sizes map[string]int) {
+	t.Helper()
+	for version, size := range sizes {
+		dir := filepath.Join(cellar, name, version)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "lib"), make([]byte, size), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestMultiVersionFormulaeRevision(t *testing.T) {
+	multi := multiVersionFormulae(packageItems([]string{"openssl@3 3.3.1 3.3.1_1", "jq 1.7.1"}))
+	if len(multi) != 1 {
+		t.Fatalf("got %d multi-version formulae, want 1: %+v", len(multi), multi)
+	}
+	if multi[0].Latest != "3.3.1_1" {
+		t.Errorf("Latest = %q; want 3.3.1_1", multi[0].Latest)
+	}
+
+	cellar := t.TempDir()
+	writeKegs(t, cellar, "openssl@3", map[string]int{"3.3.1": 100, "3.3.1_1": 7})
+	// Only the older keg is reclaimable.
+	if got := oldVersionSize(cellar, multi[0]); got != 100 {
+		t.Errorf("oldVersionSize = %d; want 100", got)
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import "sort"
+
+// naturalLess orders strings the way people read them: ASCII letters compare without
+// case, and runs of digits compare by value, so "aText.app" < "Zoom.app" and
+// "python@3.9" < "python@3.11". Strings that tie are ordered byte-wise, so the
+// result is a total order that does not depend on the locale.
+func naturalLess(a, b string) bool {
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		ca, cb := a[i], b[j]
+		if isDigit(ca) && isDigit(cb) {
+			si, sj := i, j
+			for i < len(a) && isDigit(a[i]) {
+				i++
+			}
+			for j < len(b) && isDigit(b[j]) {
+				j++
+			}
+			if c := compareDigits(a[si:i], b[sj:j]); c != 0 {
+				return c < 0
+			}
+			continue
+		}
+		if la, lb := lowerASCII(ca), lowerASCII(cb); la != lb {
+			return la < lb
+		}
+		i++
+		j++
+	}
+	if rest := (len(a) - i) - (len(b) - j); rest != 0 {
+		return rest < 0
+	}
+	return a < b
+}
+
+// compareDigits compares two digit runs by numeric value without parsing them, so
+// arbitrarily long runs work. Leading zeros are ignored.
+func compareDigits(a, b string) int {
+	for len(a) > 1 && a[0] == '0' {
+		a = a[1:]
+	}
+	for len(b) > 1 && b[0] == '0' {
+		b = b[1:]
+	}
+	switch {
+	case len(a) != len(b):
+		if len(a) < len(b) {
+			return -1
+		}
+		return 1
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+func lowerASCII(c byte) byte {
+	if c >= 'A' && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+	return c
+}
+
+// sortNatural sorts list in place with naturalLess.
+func sortNatural(list []string) {
+	sort.Slice(list, func(i, j int) bool { return naturalLess(list[i], list[j]) })
+}
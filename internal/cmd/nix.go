@@ -0,0 +1,123 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"unicode"
+)
+
+// splitNixName splits a derivation name such as "ripgrep-14.1.0" at the first
+// dash-separated part that starts with a digit.
+func splitNixName(drv string) (name, version string) {
+	parts := strings.Split(drv, "-")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" && unicode.IsDigit(rune(parts[i][0])) {
+			return strings.Join(parts[:i], "-"), strings.Join(parts[i:], "-")
+		}
+	}
+	return drv, ""
+}
+
+// storePathName returns the derivation name of /nix/store/<hash>-<name>.
+func storePathName(storePath string) string {
+	base := path.Base(storePath)
+	if _, name, ok := strings.Cut(base, "-"); ok {
+		return name
+	}
+	return base
+}
+
+type nixProfileElement struct {
+	AttrPath   string   `json:"attrPath"`
+	StorePaths []string `json:"storePaths"`
+}
+
+// parseNixProfileJSON reads `nix profile list --json`. Newer Nix keys elements by name;
+// older releases (profile version 2) emit an array, so names come from the store path.
+func parseNixProfileJSON(data []byte) ([]string, error) {
+	var doc struct {
+		Elements json.RawMessage `json:"elements"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("decode nix profile list: %w", err)
+	}
+
+	var lines []string
+	add := func(name string, elem nixProfileElement) {
+		version := ""
+		if len(elem.StorePaths) > 0 {
+			drvName, drvVersion := splitNixName(storePathName(elem.StorePaths[0]))
+			version = drvVersion
+			if name == "" {
+				name = drvName
+			}
+		}
+		if name == "" && elem.AttrPath != "" {
+			name = elem.AttrPath[strings.LastIndex(elem.AttrPath, ".")+1:]
+		}
+		if name != "" {
+			lines = append(lines, strings.TrimSpace(name+" "+version))
+		}
+	}
+
+	var byName map[string]nixProfileElement
+	var list []nixProfileElement
+	switch {
+	case json.Unmarshal(doc.Elements, &byName) == nil:
+		for name, elem := range byName {
+			add(name, elem)
+		}
+	case json.Unmarshal(doc.Elements, &list) == nil:
+		for _, elem := range list {
+			add("", elem)
+		}
+	default:
+		return nil, fmt.Errorf("decode nix profile list: unrecognized elements")
+	}
+	sortNatural(lines)
+	return lines, nil
+}
+
+// parseNixEnvQuery reads `nix-env -q` output, one derivation name per line.
+func parseNixEnvQuery(lines []string) []string {
+	packages := make([]string, 0, len(lines))
+	for _, line := range lines {
+		name, version := splitNixName(line)
+		packages = append(packages, strings.TrimSpace(name+" "+version))
+	}
+	sortNatural(packages)
+	return packages
+}
+
+// nixPackages lists the packages in the user's Nix profile, falling back to nix-env for
+// setups that predate `nix profile` (or where it rejects a nix-env managed profile).
+func (r *exportRun) nixPackages() ([]string, error) {
+	out, err := execCommand(r.ctx, "nix", "--extra-experimental-features", "nix-command", "profile", "list", "--json").Output()
+	if err == nil {
+		var packages []string
+		if packages, err = parseNixProfileJSON(out); err == nil {
+			return packages, nil
+		}
+	}
+	lines, legacyErr := commandLines(r.ctx, "nix-env", "-q")
+	if legacyErr != nil {
+		return nil, wrapCommandErr("nix profile list --json", err, "nix-env -q also failed: "+legacyErr.Error())
+	}
+	return parseNixEnvQuery(lines), nil
+}
+
+func writeNixSection(r *exportRun) error {
+	packages, err := r.nixPackages()
+	if err != nil {
+		return err
+	}
+	packages = r.opts.nameFilter.apply(packages)
+	r.stats.NixPackageCount = len(packages)
+	r.result.NixPackages = packageItems(packages)
+	return writeLines(r.w, packages)
+}
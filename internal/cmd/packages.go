@@ -0,0 +1,86 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// --report-format values for the cask and formula lists in the text report.
+const (
+	reportRaw   = "raw"
+	reportTable = "table"
+)
+
+// writePackageLines writes `brew list --versions` lines into the report: unchanged, or,
+// with table set, as aligned name and version columns. Raw stays the default so
+// reports diff cleanly from run to run.
+func writePackageLines(w io.Writer, lines []string, table bool) error {
+	if !table {
+		return writeLines(w, lines)
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, line := range lines {
+		name, versions, _ := strings.Cut(line, " ")
+		if _, err := fmt.Fprintf(tw, "%s\t%s\n", name, strings.TrimSpace(versions)); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
+
+// packageItem is one cask, formula, or port with every installed version. Homebrew keeps
+// old versions until `brew cleanup`, so a package can have several.
+type packageItem struct {
+	Name     string   `json:"name" yaml:"name" toml:"name" xml:"name"`
+	Versions []string `json:"versions" yaml:"versions" toml:"versions" xml:"versions>version"`
+}
+
+// parsePackageLine splits a `brew list --versions` line ("name 1.2 1.3") into a
+// packageItem, keeping the listed version order and dropping repeats. ok is false for
+// blank lines.
+func parsePackageLine(line string) (item packageItem, ok bool) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return item, false
+	}
+	item.Name = fields[0]
+	item.Versions = []string{}
+	seen := make(map[string]bool, len(fields)-1)
+	for _, version := range fields[1:] {
+		if !seen[version] {
+			seen[version] = true
+			item.Versions = append(item.Versions, version)
+		}
+	}
+	return item, true
+}
+
+// packageItems parses `brew list --versions` style lines into items.
+func packageItems(lines []string) []packageItem {
+	items := make([]packageItem, 0, len(lines))
+	for _, line := range lines {
+		if item, ok := parsePackageLine(line); ok {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// version joins the installed versions with spaces, as `brew list --versions` shows them.
+func (p packageItem) version() string {
+	return strings.Join(p.Versions, " ")
+}
+
+// sortedVersions returns the versions in lexical order, so two machines that installed
+// the same versions in a different order compare equal.
+func (p packageItem) sortedVersions() []string {
+	versions := append([]string(nil), p.Versions...)
+	sort.Strings(versions)
+	return versions
+}
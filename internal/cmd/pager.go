@@ -0,0 +1,62 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// defaultPager is used by --pager when $PAGER is unset; -R keeps the summary colors.
+const defaultPager = "less -R"
+
+// isTerminal reports whether w is a character device such as a TTY.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// pager feeds output to $PAGER running on the terminal. Close must be called to wait
+// for the user to quit it.
+type pager struct {
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+}
+
+// startPager runs $PAGER (through sh, so "less -R" style values work) with its output
+// on out.
+func startPager(out io.Writer) (*pager, error) {
+	command := strings.TrimSpace(os.Getenv("PAGER"))
+	if command == "" {
+		command = defaultPager
+	}
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Stdout = out
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, wrapCommandErr("$PAGER "+command, err, "Set PAGER to an installed pager, or drop --pager.")
+	}
+	return &pager{cmd: cmd, stdin: stdin}, nil
+}
+
+func (p *pager) Write(b []byte) (int, error) {
+	return p.stdin.Write(b)
+}
+
+func (p *pager) Close() error {
+	if err := p.stdin.Close(); err != nil {
+		return err
+	}
+	return p.cmd.Wait()
+}
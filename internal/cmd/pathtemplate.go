@@ -0,0 +1,61 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	arcer "github.com/yourorg/arc-sdk/errors"
+)
+
+// pathPlaceholders documents the tokens expandPathTemplate understands.
+const pathPlaceholders = "{hostname}, {user}, {date} (2006-01-02), {time} (15-04-05)"
+
+var placeholderRE = regexp.MustCompile(`\{[a-z]+\}`)
+
+// expandPathTemplate replaces placeholders such as {hostname} and {date} in an output
+// path. Unknown placeholders are an error so typos don't end up in file names.
+func expandPathTemplate(flag, path string, now time.Time) (string, error) {
+	if !strings.Contains(path, "{") {
+		return path, nil
+	}
+	values := map[string]string{
+		"{hostname}": shortHostname(),
+		"{user}":     os.Getenv("USER"),
+		"{date}":     now.Format("2006-01-02"),
+		"{time}":     now.Format("15-04-05"),
+	}
+	var unknown string
+	expanded := placeholderRE.ReplaceAllStringFunc(path, func(token string) string {
+		value, ok := values[token]
+		if !ok {
+			unknown = token
+			return token
+		}
+		return value
+	})
+	if unknown != "" {
+		return "", &arcer.CLIError{
+			Msg:  fmt.Sprintf("unknown placeholder %s in %s", unknown, flag),
+			Hint: "Available placeholders: " + pathPlaceholders,
+		}
+	}
+	return expanded, nil
+}
+
+// shortHostname returns the machine name without a domain suffix such as ".local".
+func shortHostname() string {
+	name, err := os.Hostname()
+	if err != nil || name == "" {
+		return "unknown-host"
+	}
+	if host, _, ok := strings.Cut(name, "."); ok && host != "" {
+		return host
+	}
+	return name
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+
+	arcer "github.com/yourorg/arc-sdk/errors"
+)
+
+// Pinned check outcomes.
+const (
+	pinnedMissing   = "missing"
+	pinnedTooOld    = "too-old"
+	pinnedTooNew    = "too-new"
+	pinnedDifferent = "different"
+)
+
+// pinnedManifest is a --pinned file of approved versions:
+//
+//	casks:
+//	  firefox: "128.0"
+//	formulae:
+//	  git: "2.45.2"
+//	  jq: "*"
+//
+// A version of "*" (or empty) only requires the package to be installed.
+type pinnedManifest struct {
+	Casks    map[string]string `yaml:"casks"`
+	Formulae map[string]string `yaml:"formulae"`
+}
+
+// pinnedViolation is an installed cask or formula that does not match its pin.
+type pinnedViolation struct {
+	Kind      string `json:"kind" yaml:"kind" toml:"kind" xml:"kind"`
+	Name      string `json:"name" yaml:"name" toml:"name" xml:"name"`
+	Pinned    string `json:"pinned" yaml:"pinned" toml:"pinned" xml:"pinned"`
+	Installed string `json:"installed,omitempty" yaml:"installed,omitempty" toml:"installed,omitempty" xml:"installed,omitempty"`
+	Status    string `json:"status" yaml:"status" toml:"status" xml:"status"`
+}
+
+func loadPinnedManifest(path string) (*pinnedManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, &arcer.CLIError{
+			Msg:  fmt.Sprintf("read --pinned: %v", err),
+			Hint: "Pass a YAML file with casks: and formulae: maps of name to version.",
+		}
+	}
+	var pinned pinnedManifest
+	if err := yaml.Unmarshal(data, &pinned); err != nil {
+		return nil, &arcer.CLIError{
+			Msg:  fmt.Sprintf("parse --pinned %s: %v", path, err),
+			Hint: "Expected casks: and formulae: maps of name to version, e.g. `git: \"2.45.2\"`.",
+		}
+	}
+	return &pinned, nil
+}
+
+// pinnedStatus compares an installed version with its pin. Versions that don't parse
+// as numbers must match exactly.
+func pinnedStatus(installed, pinned string) string {
+	if pinned == "" || pinned == "*" || installed == pinned {
+		return ""
+	}
+	have, ok1 := versionParts(installed)
+	want, ok2 := versionParts(pinned)
+	if !ok1 || !ok2 {
+		return pinnedDifferent
+	}
+	switch compareParts(have, want) {
+	case -1:
+		return pinnedTooOld
+	case 1:
+		return pinnedTooNew
+	}
+	return ""
+}
+
+// checkPinned returns the violations of pins against the installed items, sorted by
+// name.
+func checkPinned(kind string, pins map[string]string, installed []packageItem) []pinnedViolation {
+	byName := make(map[string]packageItem, len(installed))
+	for _, item := range installed {
+		byName[item.Name] = item
+	}
+	var violations []pinnedViolation
+	for name, pinned := range pins {
+		item, ok := byName[name]
+		if !ok {
+			violations = append(violations, pinnedViolation{Kind: kind, Name: name, Pinned: pinned, Status: pinnedMissing})
+			continue
+		}
+		version := highestVersion(item.Versions)
+		if status := pinnedStatus(version, pinned); status != "" {
+			violations = append(violations, pinnedViolation{Kind: kind, Name: name, Pinned: pinned, Installed: version, Status: status})
+		}
+	}
+	sort.Slice(violations, func(i, j int) bool { return violations[i].Name < violations[j].Name })
+	return violations
+}
+
+// writePinnedSection compares installed casks and formulae with --pinned. The
+// unfiltered lists are used, so --name-filter or --leaves-only never hide a pinned
+// package. Each violation is also a warning, so --fail-on-warnings turns drift into a
+// failed run.
+func writePinnedSection(r *exportRun) error {
+	if _, err := r.loadCasks(); err != nil {
+		return err
+	}
+	if _, err := r.loadFormulae(); err != nil {
+		return err
+	}
+	pinned := r.opts.pinned
+	violations := append(checkPinned("cask", pinned.Casks, packageItems(r.rawCasks)),
+		checkPinned("formula", pinned.Formulae, packageItems(r.rawFormulae))...)
+	r.result.PinnedViolations = violations
+	r.stats.PinnedViolationCount = len(violations)
+
+	lines := []string{fmt.Sprintf("Pinned: %s (%d casks, %d formulae)", r.opts.pinnedPath, len(pinned.Casks), len(pinned.Formulae)), ""}
+	for _, v := range violations {
+		line := fmt.Sprintf("%s %s: pinned %s, not installed", v.Kind, v.Name, v.Pinned)
+		if v.Status != pinnedMissing {
+			line = fmt.Sprintf("%s %s: installed %s, pinned %s (%s)", v.Kind, v.Name, v.Installed, v.Pinned, v.Status)
+		}
+		r.warn("pinned " + line)
+		lines = append(lines, line)
+	}
+	lines = append(lines, fmt.Sprintf("(%d violation(s))", len(violations)))
+	return writeLines(r.w, lines)
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	arcer "github.com/yourorg/arc-sdk/errors"
+)
+
+// exportProfile presets export flags for a common kind of audit. Flags given on the
+// command line always win over the profile.
+type exportProfile struct {
+	description string
+	flags       map[string]string
+}
+
+var exportProfiles = map[string]exportProfile{
+	"security": {
+		description: "apps, launchd, login items, and browser extensions with architecture and quarantine checks",
+		flags: map[string]string{
+			"sections":        "apps,launchd,login-items,browser-extensions,arch",
+			"with-arch":       "true",
+			"with-quarantine": "true",
+		},
+	},
+	"dev": {
+		description: "package managers, toolchains, and language runtimes",
+		flags: map[string]string{
+			"sections":    "formulae,casks,brew-prefixes,macports,go-binaries,rust,runtimes",
+			"leaves-only": "true",
+		},
+	},
+	"minimal": {
+		description: "apps, casks, and formulae as compact JSON",
+		flags: map[string]string{
+			"sections":     "apps,casks,formulae",
+			"compact":      "true",
+			"output":       "json",
+			"compact-json": "true",
+		},
+	},
+}
+
+func profileNames() []string {
+	names := make([]string, 0, len(exportProfiles))
+	for name := range exportProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyProfile sets every flag the named profile defines unless the user already set
+// it. It must run before any flag value is read.
+func applyProfile(cmd *cobra.Command, name string) error {
+	profile, ok := exportProfiles[strings.ToLower(name)]
+	if !ok {
+		var valid []string
+		for _, n := range profileNames() {
+			valid = append(valid, fmt.Sprintf("%s (%s)", n, exportProfiles[n].description))
+		}
+		return &arcer.CLIError{
+			Msg:  fmt.Sprintf("unknown profile %q", name),
+			Hint: "Valid profiles: " + strings.Join(valid, "; "),
+		}
+	}
+
+	flags := make([]string, 0, len(profile.flags))
+	for flag := range profile.flags {
+		flags = append(flags, flag)
+	}
+	sort.Strings(flags)
+	for _, flag := range flags {
+		if cmd.Flags().Changed(flag) {
+			continue
+		}
+		if err := cmd.Flags().Set(flag, profile.flags[flag]); err != nil {
+			return fmt.Errorf("profile %s: set --%s: %w", name, flag, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// quarantineApproved is set in a quarantine value's flags once the user has opened the
+// app past the Gatekeeper prompt.
+const quarantineApproved = 0x40
+
+// quarantinedApp is an app bundle carrying the com.apple.quarantine xattr
+// (--with-quarantine). Agent is the app that downloaded it.
+type quarantinedApp struct {
+	Path         string    `json:"path" yaml:"path" toml:"path" xml:"path"`
+	Agent        string    `json:"agent,omitempty" yaml:"agent,omitempty" toml:"agent,omitempty" xml:"agent,omitempty"`
+	DownloadedAt time.Time `json:"downloaded_at,omitempty" yaml:"downloaded_at,omitempty" toml:"downloaded_at,omitempty" xml:"downloaded_at,omitempty"`
+	Opened       bool      `json:"opened" yaml:"opened" toml:"opened" xml:"opened"`
+}
+
+// quarantineValue reads an app's com.apple.quarantine xattr. ok is false when the
+// bundle has none, which xattr reports as a failure with "No such xattr".
+func quarantineValue(ctx context.Context, path string) (value string, ok bool, err error) {
+	var stderr bytes.Buffer
+	cmd := execCommand(ctx, "xattr", "-p", "com.apple.quarantine", path)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if strings.Contains(stderr.String(), "No such xattr") {
+			return "", false, nil
+		}
+		return "", false, wrapCommandErr("xattr -p com.apple.quarantine "+path, err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), true, nil
+}
+
+// parseQuarantine reads a quarantine value of the form
+// "flags;hex-timestamp;agent;event-uuid", e.g. "0083;65a1f0c2;Safari;8E1B...". Fields
+// that are missing or malformed are left empty.
+func parseQuarantine(path, value string) quarantinedApp {
+	app := quarantinedApp{Path: path}
+	fields := strings.Split(value, ";")
+	if flags, err := strconv.ParseUint(fields[0], 16, 32); err == nil {
+		app.Opened = flags&quarantineApproved != 0
+	}
+	if len(fields) > 1 {
+		if sec, err := strconv.ParseInt(fields[1], 16, 64); err == nil && sec > 0 {
+			app.DownloadedAt = time.Unix(sec, 0).UTC()
+		}
+	}
+	if len(fields) > 2 {
+		app.Agent = fields[2]
+	}
+	return app
+}
+
+// quarantinedApps checks every bundle for the quarantine xattr, up to workers at a
+// time. Bundles xattr cannot read are returned in failed rather than stopping the scan.
+func quarantinedApps(ctx context.Context, bundles []string, workers int) (apps []quarantinedApp, failed []string) {
+	type check struct {
+		value string
+		ok    bool
+		err   error
+	}
+	checks := parallelMap(workers, bundles, func(bundle string) check {
+		value, ok, err := quarantineValue(ctx, bundle)
+		return check{value, ok, err}
+	})
+	for i, bundle := range bundles {
+		switch c := checks[i]; {
+		case c.err != nil:
+			failed = append(failed, bundle)
+		case c.ok:
+			apps = append(apps, parseQuarantine(bundle, c.value))
+		}
+	}
+	return apps, failed
+}
+
+// writeQuarantinedApps lists the quarantined bundles after the app list, e.g.
+// "/Applications/Tool.app (Safari, 2024-01-13, never opened)".
+func (r *exportRun) writeQuarantinedApps(bundles []string) error {
+	start := time.Now()
+	apps, failed := quarantinedApps(r.ctx, bundles, r.opts.concurrency)
+	if len(failed) > 0 {
+		r.warn(fmt.Sprintf("quarantine xattr unreadable for %d app bundle(s): %s", len(failed), strings.Join(failed, ", ")))
+	}
+	r.result.QuarantinedApps = apps
+	r.stats.QuarantinedAppCount = len(apps)
+
+	if _, err := fmt.Fprintln(r.w, "\n-- Quarantined (com.apple.quarantine) ---"); err != nil {
+		return err
+	}
+	lines := make([]string, 0, len(apps))
+	for _, app := range apps {
+		var details []string
+		if app.Agent != "" {
+			details = append(details, app.Agent)
+		}
+		if !app.DownloadedAt.IsZero() {
+			details = append(details, app.DownloadedAt.Format("2006-01-02"))
+		}
+		if !app.Opened {
+			details = append(details, "never opened")
+		}
+		line := r.displayPath(app.Path)
+		if len(details) > 0 {
+			line += " (" + strings.Join(details, ", ") + ")"
+		}
+		lines = append(lines, line)
+	}
+	if err := writeLines(r.w, lines); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(r.w, "(%d quarantined)\n", len(apps)); err != nil {
+		return err
+	}
+	r.track("quarantine", start)
+	return nil
+}
@@ -0,0 +1,28 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseQuarantine(t *testing.T) {
+	tests := []struct {
+		value string
+		want  quarantinedApp
+	}{
+		{"0083;65a1f0c2;Safari;8E1B2C3D", quarantinedApp{Path: "/A.app", Agent: "Safari", DownloadedAt: time.Unix(0x65a1f0c2, 0).UTC()}},
+		{"00c3;65a1f0c2;Chrome;", quarantinedApp{Path: "/A.app", Agent: "Chrome", DownloadedAt: time.Unix(0x65a1f0c2, 0).UTC(), Opened: true}},
+		{"0083;0;;", quarantinedApp{Path: "/A.app"}},
+		{"0083", quarantinedApp{Path: "/A.app"}},
+		{"zz;nothex;Mail", quarantinedApp{Path: "/A.app", Agent: "Mail"}},
+		{"", quarantinedApp{Path: "/A.app"}},
+	}
+	for _, tt := range tests {
+		if got := parseQuarantine("/A.app", tt.value); got != tt.want {
+			t.Errorf("parseQuarantine(%q) = %+v, want %+v", tt.value, got, tt.want)
+		}
+	}
+}
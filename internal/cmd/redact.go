@@ -0,0 +1,170 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"strings"
+)
+
+// homeRedactor replaces the home directory with "~" wherever it begins a path. A match
+// must be bounded on both sides, so /Users/al never rewrites /Users/alice.
+type homeRedactor struct {
+	home string
+}
+
+func newHomeRedactor(home string) homeRedactor {
+	home = strings.TrimSuffix(home, "/")
+	if home == "" {
+		return homeRedactor{}
+	}
+	return homeRedactor{home: home}
+}
+
+func (h homeRedactor) enabled() bool {
+	return h.home != ""
+}
+
+func (h homeRedactor) redact(s string) string {
+	if !h.enabled() || !strings.Contains(s, h.home) {
+		return s
+	}
+	var b strings.Builder
+	for {
+		i := strings.Index(s, h.home)
+		if i < 0 {
+			b.WriteString(s)
+			return b.String()
+		}
+		end := i + len(h.home)
+		before := i == 0 || strings.IndexByte(" \t(=:,\"'[<", s[i-1]) >= 0
+		after := end == len(s) || strings.IndexByte("/ \t\n)\"',:]>", s[end]) >= 0
+		b.WriteString(s[:i])
+		if before && after {
+			b.WriteString("~")
+		} else {
+			b.WriteString(h.home)
+		}
+		s = s[end:]
+	}
+}
+
+func (h homeRedactor) redactAll(values []string) []string {
+	if !h.enabled() || values == nil {
+		return values
+	}
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = h.redact(v)
+	}
+	return out
+}
+
+func (h homeRedactor) redactMap(values stringMap) stringMap {
+	if !h.enabled() || values == nil {
+		return values
+	}
+	out := make(stringMap, len(values))
+	for key, value := range values {
+		out[key] = h.redact(value)
+	}
+	return out
+}
+
+// redactResult rewrites every path-bearing field of result for display. File operations
+// must use the unredacted result.
+func (h homeRedactor) redactResult(result exportResult) exportResult {
+	if !h.enabled() {
+		return result
+	}
+	result.ReportPath = h.redact(result.ReportPath)
+	result.BrewPath = h.redact(result.BrewPath)
+	result.BrewJSONPath = h.redact(result.BrewJSONPath)
+	result.DependencyGraphPath = h.redact(result.DependencyGraphPath)
+	result.Apps = h.redactAll(result.Apps)
+	if result.LargestApps != nil {
+		apps := make([]appSize, len(result.LargestApps))
+		for i, app := range result.LargestApps {
+			apps[i] = appSize{Path: h.redact(app.Path), SizeBytes: app.SizeBytes}
+		}
+		result.LargestApps = apps
+	}
+	if result.AppSymlinks != nil {
+		links := make([]appSymlink, len(result.AppSymlinks))
+		for i, link := range result.AppSymlinks {
+			links[i] = appSymlink{Path: h.redact(link.Path), Target: h.redact(link.Target), Broken: link.Broken}
+		}
+		result.AppSymlinks = links
+	}
+	result.UnmanagedApps = h.redactAll(result.UnmanagedApps)
+	result.CommandsRun = h.redactAll(result.CommandsRun)
+	if result.AppMetadata != nil {
+		meta := make([]appMetadata, len(result.AppMetadata))
+		for i, app := range result.AppMetadata {
+			app.Path = h.redact(app.Path)
+			meta[i] = app
+		}
+		result.AppMetadata = meta
+	}
+	if result.QuarantinedApps != nil {
+		apps := make([]quarantinedApp, len(result.QuarantinedApps))
+		for i, app := range result.QuarantinedApps {
+			app.Path = h.redact(app.Path)
+			apps[i] = app
+		}
+		result.QuarantinedApps = apps
+	}
+	if result.AppDirCounts != nil {
+		dirs := make([]appDirCount, len(result.AppDirCounts))
+		for i, dir := range result.AppDirCounts {
+			dirs[i] = appDirCount{Path: h.redact(dir.Path), Count: dir.Count}
+		}
+		result.AppDirCounts = dirs
+	}
+	result.BrewPrefixes = h.redactAll(result.BrewPrefixes)
+	result.PrunedReports = h.redactAll(result.PrunedReports)
+	result.Warnings = h.redactAll(result.Warnings)
+	result.BrewEnv = h.redactMap(result.BrewEnv)
+	result.BrewConfig = h.redactMap(result.BrewConfig)
+	if diff := result.BaselineDiff; diff != nil {
+		result.BaselineDiff = &baselineDiff{
+			Path:    h.redact(diff.Path),
+			Added:   h.redactAll(diff.Added),
+			Removed: h.redactAll(diff.Removed),
+		}
+	}
+	return result
+}
+
+// redactingWriter applies a homeRedactor line by line. Flush writes any trailing
+// partial line.
+type redactingWriter struct {
+	w   io.Writer
+	r   homeRedactor
+	buf []byte
+}
+
+func (rw *redactingWriter) Write(p []byte) (int, error) {
+	rw.buf = append(rw.buf, p...)
+	for {
+		i := bytes.IndexByte(rw.buf, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		if _, err := io.WriteString(rw.w, rw.r.redact(string(rw.buf[:i+1]))); err != nil {
+			return 0, err
+		}
+		rw.buf = rw.buf[i+1:]
+	}
+}
+
+func (rw *redactingWriter) Flush() error {
+	if len(rw.buf) == 0 {
+		return nil
+	}
+	_, err := io.WriteString(rw.w, rw.r.redact(string(rw.buf)))
+	rw.buf = nil
+	return err
+}
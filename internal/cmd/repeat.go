@@ -0,0 +1,84 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// runRepeat runs the export n times for timing (--repeat) and prints each run's
+// duration, then min/mean/max overall and the mean per section. Reports and brew JSON
+// go to a temporary directory that is removed afterwards; no result is rendered or
+// published. A failed run stops the loop.
+func runRepeat(ctx context.Context, opts exportOptions, n int, w io.Writer) error {
+	dir, err := os.MkdirTemp("", "arc-apps-repeat-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	opts.outputDir, opts.keepReports = "", 0
+	opts.appendReport = false
+	opts.emit = nil
+
+	durations := make([]time.Duration, 0, n)
+	sectionTotals := make(sectionTimings)
+	for i := 1; i <= n; i++ {
+		opts.reportPath = filepath.Join(dir, fmt.Sprintf("report-%d.txt", i))
+		opts.jsonPath = filepath.Join(dir, fmt.Sprintf("brew-%d.json", i))
+		result, err := runExport(ctx, opts)
+		if err != nil {
+			return fmt.Errorf("run %d/%d: %w", i, n, err)
+		}
+		elapsed := result.CompletedAt.Sub(result.StartedAt)
+		durations = append(durations, elapsed)
+		for name, seconds := range result.SectionTimings {
+			sectionTotals[name] += seconds
+		}
+		fmt.Fprintf(w, "run %d/%d: %s (%d warnings)\n", i, n, elapsed.Round(time.Millisecond), len(result.Warnings))
+	}
+	return writeRepeatStats(w, durations, sectionTotals)
+}
+
+func writeRepeatStats(w io.Writer, durations []time.Duration, sectionTotals sectionTimings) error {
+	if len(durations) == 0 {
+		return nil
+	}
+	var total time.Duration
+	fastest, slowest := durations[0], durations[0]
+	for _, d := range durations {
+		total += d
+		fastest = min(fastest, d)
+		slowest = max(slowest, d)
+	}
+	runs := len(durations)
+	fmt.Fprintf(w, "\n%d runs: min %s, mean %s, max %s\n", runs,
+		fastest.Round(time.Millisecond), (total / time.Duration(runs)).Round(time.Millisecond), slowest.Round(time.Millisecond))
+
+	names := make([]string, 0, len(sectionTotals))
+	for name := range sectionTotals {
+		names = append(names, name)
+	}
+	// Slowest sections first: they are the ones worth tuning.
+	sort.Slice(names, func(i, j int) bool {
+		if sectionTotals[names[i]] != sectionTotals[names[j]] {
+			return sectionTotals[names[i]] > sectionTotals[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "\nMean per section:")
+	for _, name := range names {
+		mean := time.Duration(sectionTotals[name] / float64(runs) * float64(time.Second))
+		fmt.Fprintf(tw, "  %s:\t%s\n", name, mean.Round(time.Millisecond))
+	}
+	return tw.Flush()
+}
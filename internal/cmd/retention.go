@@ -0,0 +1,74 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+)
+
+// timestampedReportRE matches only the default report names this tool generates.
+var timestampedReportRE = regexp.MustCompile(`^` + regexp.QuoteMeta(reportFilePrefix) + `\d{4}-\d{2}-\d{2}_\d{2}-\d{2}-\d{2}\.txt$`)
+
+// latestReportName is the symlink in --output-dir that always points at the newest
+// report, giving scripts a fixed path to read.
+const latestReportName = reportFilePrefix + "latest.txt"
+
+// updateLatestLink points dir/latestReportName at report. The link is relative when
+// report is inside dir, so the directory can be moved or synced as a whole, and it is
+// swapped in with a rename so readers never find it missing.
+func updateLatestLink(dir, report string) error {
+	link := filepath.Join(dir, latestReportName)
+	target := report
+	if rel, err := filepath.Rel(dir, report); err == nil && filepath.IsLocal(rel) {
+		target = rel
+	}
+	tmp := filepath.Join(dir, "."+latestReportName+".tmp")
+	os.Remove(tmp)
+	if err := os.Symlink(target, tmp); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, link); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// pruneReports deletes the oldest timestamped reports in dir beyond the newest keep.
+// current is never removed. It returns the deleted paths and a warning per failure.
+func pruneReports(dir string, keep int, current string) ([]string, []string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, []string{fmt.Sprintf("report retention skipped: %v", err)}
+	}
+
+	var reports []string
+	for _, entry := range entries {
+		if entry.Type().IsRegular() && timestampedReportRE.MatchString(entry.Name()) {
+			reports = append(reports, filepath.Join(dir, entry.Name()))
+		}
+	}
+	// Timestamps in the names sort chronologically.
+	sort.Strings(reports)
+	if len(reports) <= keep {
+		return nil, nil
+	}
+
+	var removed, warnings []string
+	for _, path := range reports[:len(reports)-keep] {
+		if path == current {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			warnings = append(warnings, fmt.Sprintf("could not remove old report %s: %v", path, err))
+			continue
+		}
+		removed = append(removed, path)
+	}
+	return removed, warnings
+}
@@ -8,16 +8,22 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"text/tabwriter"
+	"text/template"
 	"time"
 
+	"github.com/BurntSushi/toml"
 	"github.com/dustin/go-humanize"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
@@ -39,45 +45,335 @@ func NewRootCmd() *cobra.Command {
 	}
 
 	cmd.AddCommand(exportCmd())
+	cmd.AddCommand(schemaCmd())
+	cmd.AddCommand(verifyCmd())
+	cmd.AddCommand(matrixCmd())
+	cmd.AddCommand(capabilitiesCmd())
 	return cmd
 }
 
 type exportStats struct {
-	AppBundleCount        int `json:"app_bundle_count" yaml:"app_bundle_count"`
-	ApplicationsDirCount  int `json:"applications_dir_count" yaml:"applications_dir_count"`
-	UserApplicationsCount int `json:"user_applications_count" yaml:"user_applications_count"`
-	BrewCaskCount         int `json:"brew_cask_count" yaml:"brew_cask_count"`
-	BrewFormulaCount      int `json:"brew_formula_count" yaml:"brew_formula_count"`
+	AppBundleCount           int `json:"app_bundle_count" yaml:"app_bundle_count" toml:"app_bundle_count" xml:"app_bundle_count"`
+	ApplicationsDirCount     int `json:"applications_dir_count" yaml:"applications_dir_count" toml:"applications_dir_count" xml:"applications_dir_count"`
+	UserApplicationsCount    int `json:"user_applications_count" yaml:"user_applications_count" toml:"user_applications_count" xml:"user_applications_count"`
+	BrewCaskCount            int `json:"brew_cask_count" yaml:"brew_cask_count" toml:"brew_cask_count" xml:"brew_cask_count"`
+	BrewFormulaCount         int `json:"brew_formula_count" yaml:"brew_formula_count" toml:"brew_formula_count" xml:"brew_formula_count"`
+	LeafFormulaCount         int `json:"leaf_formula_count" yaml:"leaf_formula_count" toml:"leaf_formula_count" xml:"leaf_formula_count"`
+	LaunchItemCount          int `json:"launch_item_count" yaml:"launch_item_count" toml:"launch_item_count" xml:"launch_item_count"`
+	LoginItemCount           int `json:"login_item_count" yaml:"login_item_count" toml:"login_item_count" xml:"login_item_count"`
+	GoBinaryCount            int `json:"go_binary_count" yaml:"go_binary_count" toml:"go_binary_count" xml:"go_binary_count"`
+	CargoCrateCount          int `json:"cargo_crate_count" yaml:"cargo_crate_count" toml:"cargo_crate_count" xml:"cargo_crate_count"`
+	RuntimeVersionCount      int `json:"runtime_version_count" yaml:"runtime_version_count" toml:"runtime_version_count" xml:"runtime_version_count"`
+	IntelOnlyCount           int `json:"intel_only_count" yaml:"intel_only_count" toml:"intel_only_count" xml:"intel_only_count"`
+	MacPortsCount            int `json:"macports_count" yaml:"macports_count" toml:"macports_count" xml:"macports_count"`
+	NixPackageCount          int `json:"nix_package_count" yaml:"nix_package_count" toml:"nix_package_count" xml:"nix_package_count"`
+	InaccessibleAppCount     int `json:"inaccessible_app_count" yaml:"inaccessible_app_count" toml:"inaccessible_app_count" xml:"inaccessible_app_count"`
+	ExcludedSystemAppCount   int `json:"excluded_system_app_count" yaml:"excluded_system_app_count" toml:"excluded_system_app_count" xml:"excluded_system_app_count"`
+	StaleCaskCount           int `json:"stale_cask_count" yaml:"stale_cask_count" toml:"stale_cask_count" xml:"stale_cask_count"`
+	DeprecatedFormulaCount   int `json:"deprecated_formula_count" yaml:"deprecated_formula_count" toml:"deprecated_formula_count" xml:"deprecated_formula_count"`
+	BrowserExtensionCount    int `json:"browser_extension_count" yaml:"browser_extension_count" toml:"browser_extension_count" xml:"browser_extension_count"`
+	BehindCount              int `json:"behind_count" yaml:"behind_count" toml:"behind_count" xml:"behind_count"`
+	UnmanagedAppCount        int `json:"unmanaged_app_count" yaml:"unmanaged_app_count" toml:"unmanaged_app_count" xml:"unmanaged_app_count"`
+	QuarantinedAppCount      int `json:"quarantined_app_count" yaml:"quarantined_app_count" toml:"quarantined_app_count" xml:"quarantined_app_count"`
+	AppConflictCount         int `json:"app_conflict_count" yaml:"app_conflict_count" toml:"app_conflict_count" xml:"app_conflict_count"`
+	PinnedViolationCount     int `json:"pinned_violation_count" yaml:"pinned_violation_count" toml:"pinned_violation_count" xml:"pinned_violation_count"`
+	TapCount                 int `json:"tap_count" yaml:"tap_count" toml:"tap_count" xml:"tap_count"`
+	TotalItemCount           int `json:"total_item_count" yaml:"total_item_count" toml:"total_item_count" xml:"total_item_count"`
+	MultiVersionFormulaCount int `json:"multi_version_formula_count" yaml:"multi_version_formula_count" toml:"multi_version_formula_count" xml:"multi_version_formula_count"`
 }
 
 type exportResult struct {
-	ReportPath        string      `json:"report_path" yaml:"report_path"`
-	ReportSizeBytes   int64       `json:"report_size_bytes" yaml:"report_size_bytes"`
-	BrewJSONPath      string      `json:"brew_json_path" yaml:"brew_json_path"`
-	BrewJSONSizeBytes int64       `json:"brew_json_size_bytes" yaml:"brew_json_size_bytes"`
-	Compact           bool        `json:"compact" yaml:"compact"`
-	Stats             exportStats `json:"stats" yaml:"stats"`
-	DurationSeconds   float64     `json:"duration_seconds" yaml:"duration_seconds"`
-	StartedAt         time.Time   `json:"started_at" yaml:"started_at"`
-	CompletedAt       time.Time   `json:"completed_at" yaml:"completed_at"`
-	Warnings          []string    `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+	SchemaVersion       string      `json:"schema_version" yaml:"schema_version" toml:"schema_version" xml:"schema_version"`
+	Hostname            string      `json:"hostname" yaml:"hostname" toml:"hostname" xml:"hostname"`
+	MacOSVersion        string      `json:"macos_version" yaml:"macos_version" toml:"macos_version" xml:"macos_version"`
+	HardwareModel       string      `json:"hardware_model" yaml:"hardware_model" toml:"hardware_model" xml:"hardware_model"`
+	RosettaInstalled    bool        `json:"rosetta_installed,omitempty" yaml:"rosetta_installed,omitempty" toml:"rosetta_installed,omitempty" xml:"rosetta_installed,omitempty"`
+	BrewEmulated        bool        `json:"brew_emulated,omitempty" yaml:"brew_emulated,omitempty" toml:"brew_emulated,omitempty" xml:"brew_emulated,omitempty"`
+	ManifestHash        string      `json:"manifest_hash" yaml:"manifest_hash" toml:"manifest_hash" xml:"manifest_hash"`
+	BrewPath            string      `json:"brew_path,omitempty" yaml:"brew_path,omitempty" toml:"brew_path,omitempty" xml:"brew_path,omitempty"`
+	ReportPath          string      `json:"report_path" yaml:"report_path" toml:"report_path" xml:"report_path"`
+	ReportSizeBytes     int64       `json:"report_size_bytes" yaml:"report_size_bytes" toml:"report_size_bytes" xml:"report_size_bytes"`
+	ReportAppended      bool        `json:"report_appended,omitempty" yaml:"report_appended,omitempty" toml:"report_appended,omitempty" xml:"report_appended,omitempty"`
+	BrewJSONPath        string      `json:"brew_json_path" yaml:"brew_json_path" toml:"brew_json_path" xml:"brew_json_path"`
+	BrewJSONSizeBytes   int64       `json:"brew_json_size_bytes" yaml:"brew_json_size_bytes" toml:"brew_json_size_bytes" xml:"brew_json_size_bytes"`
+	BrewJSONFromCache   bool        `json:"brew_json_from_cache,omitempty" yaml:"brew_json_from_cache,omitempty" toml:"brew_json_from_cache,omitempty" xml:"brew_json_from_cache,omitempty"`
+	DependencyGraphPath string      `json:"dependency_graph_path,omitempty" yaml:"dependency_graph_path,omitempty" toml:"dependency_graph_path,omitempty" xml:"dependency_graph_path,omitempty"`
+	Compact             bool        `json:"compact" yaml:"compact" toml:"compact" xml:"compact"`
+	BrewSkipped         bool        `json:"brew_skipped,omitempty" yaml:"brew_skipped,omitempty" toml:"brew_skipped,omitempty" xml:"brew_skipped,omitempty"`
+	SummaryOnly         bool        `json:"summary_only,omitempty" yaml:"summary_only,omitempty" toml:"summary_only,omitempty" xml:"summary_only,omitempty"`
+	LeavesOnly          bool        `json:"leaves_only,omitempty" yaml:"leaves_only,omitempty" toml:"leaves_only,omitempty" xml:"leaves_only,omitempty"`
+	ArchChecked         bool        `json:"arch_checked,omitempty" yaml:"arch_checked,omitempty" toml:"arch_checked,omitempty" xml:"arch_checked,omitempty"`
+	Stats               exportStats `json:"stats" yaml:"stats" toml:"stats" xml:"stats"`
+	// Apps, Casks, Formulae, MacPorts, and NixPackages hold the items behind the matching
+	// counts in Stats. CollectorItems come from --collector executables. TruncatedApps
+	// is set when --max-apps cut Apps short.
+	Apps           []string        `json:"apps,omitempty" yaml:"apps,omitempty" toml:"apps,omitempty" xml:"apps>app,omitempty"`
+	TruncatedApps  bool            `json:"truncated_apps,omitempty" yaml:"truncated_apps,omitempty" toml:"truncated_apps,omitempty" xml:"truncated_apps,omitempty"`
+	Casks          []packageItem   `json:"casks,omitempty" yaml:"casks,omitempty" toml:"casks,omitempty" xml:"casks>cask,omitempty"`
+	Formulae       []packageItem   `json:"formulae,omitempty" yaml:"formulae,omitempty" toml:"formulae,omitempty" xml:"formulae>formula,omitempty"`
+	MacPorts       []packageItem   `json:"macports,omitempty" yaml:"macports,omitempty" toml:"macports,omitempty" xml:"macports>port,omitempty"`
+	NixPackages    []packageItem   `json:"nix_packages,omitempty" yaml:"nix_packages,omitempty" toml:"nix_packages,omitempty" xml:"nix_packages>package,omitempty"`
+	CollectorItems []collectorItem `json:"collector_items,omitempty" yaml:"collector_items,omitempty" toml:"collector_items,omitempty" xml:"collector_items>item,omitempty"`
+	// BrowserExtensions lists the Safari, Chromium-family, and Firefox extensions found per profile.
+	BrowserExtensions []browserExtension `json:"browser_extensions,omitempty" yaml:"browser_extensions,omitempty" toml:"browser_extensions,omitempty" xml:"browser_extensions>extension,omitempty"`
+	// LargestApps lists the biggest app bundles; it is only set with --with-sizes.
+	LargestApps []appSize `json:"largest_apps,omitempty" yaml:"largest_apps,omitempty" toml:"largest_apps,omitempty" xml:"largest_apps>app,omitempty"`
+	// AppSymlinks lists the symlinked /Applications entries; it is only set with --resolve-symlinks.
+	AppSymlinks []appSymlink `json:"app_symlinks,omitempty" yaml:"app_symlinks,omitempty" toml:"app_symlinks,omitempty" xml:"app_symlinks>link,omitempty"`
+	// UnmanagedApps are Applications entries that no cask placed and the App Store did not install.
+	UnmanagedApps []string `json:"unmanaged_apps,omitempty" yaml:"unmanaged_apps,omitempty" toml:"unmanaged_apps,omitempty" xml:"unmanaged_apps>app,omitempty"`
+	// AppConflicts are the entries in both /Applications and ~/Applications; with
+	// --merge-user-and-system-apps only the /Applications copy is counted and listed.
+	AppConflicts []string `json:"app_conflicts,omitempty" yaml:"app_conflicts,omitempty" toml:"app_conflicts,omitempty" xml:"app_conflicts>app,omitempty"`
+	// AppDirCounts holds the bundles found in each --app-dir; those bundles are also in Apps.
+	AppDirCounts []appDirCount `json:"app_dir_counts,omitempty" yaml:"app_dir_counts,omitempty" toml:"app_dir_counts,omitempty" xml:"app_dir_counts>dir,omitempty"`
+	// AppMetadata holds each app's Spotlight version, kind, and Finder tags; it is only set with --with-metadata.
+	AppMetadata []appMetadata `json:"app_metadata,omitempty" yaml:"app_metadata,omitempty" toml:"app_metadata,omitempty" xml:"app_metadata>app,omitempty"`
+	// QuarantinedApps lists the apps carrying the quarantine xattr; it is only set with --with-quarantine.
+	QuarantinedApps []quarantinedApp `json:"quarantined_apps,omitempty" yaml:"quarantined_apps,omitempty" toml:"quarantined_apps,omitempty" xml:"quarantined_apps>app,omitempty"`
+	// DeprecatedFormulae lists installed formulae Homebrew has deprecated or disabled;
+	// BehindFormulae, set by --min-version-age, those trailing the stable release.
+	DeprecatedFormulae []deprecatedFormula `json:"deprecated_formulae,omitempty" yaml:"deprecated_formulae,omitempty" toml:"deprecated_formulae,omitempty" xml:"deprecated_formulae>formula,omitempty"`
+	BehindFormulae     []behindFormula     `json:"behind_formulae,omitempty" yaml:"behind_formulae,omitempty" toml:"behind_formulae,omitempty" xml:"behind_formulae>formula,omitempty"`
+	DurationSeconds    float64             `json:"duration_seconds" yaml:"duration_seconds" toml:"duration_seconds" xml:"duration_seconds"`
+	Duration           string              `json:"duration" yaml:"duration" toml:"duration" xml:"duration"`
+	StartedAt          time.Time           `json:"started_at" yaml:"started_at" toml:"started_at" xml:"started_at"`
+	CompletedAt        time.Time           `json:"completed_at" yaml:"completed_at" toml:"completed_at" xml:"completed_at"`
+	Warnings           []string            `json:"warnings,omitempty" yaml:"warnings,omitempty" toml:"warnings,omitempty" xml:"warnings>warning,omitempty"`
+	CommandsRun        []string            `json:"commands_run,omitempty" yaml:"commands_run,omitempty" toml:"commands_run,omitempty" xml:"commands_run>command,omitempty"`
+	BrewPrefixes       []string            `json:"brew_prefixes,omitempty" yaml:"brew_prefixes,omitempty" toml:"brew_prefixes,omitempty" xml:"brew_prefixes>prefix,omitempty"`
+	PrunedReports      []string            `json:"pruned_reports,omitempty" yaml:"pruned_reports,omitempty" toml:"pruned_reports,omitempty" xml:"pruned_reports>path,omitempty"`
+	UploadedURIs       []string            `json:"uploaded_uris,omitempty" yaml:"uploaded_uris,omitempty" toml:"uploaded_uris,omitempty" xml:"uploaded_uris>uri,omitempty"`
+	// FailedSections names the sections that errored. The export stops at the first one
+	// unless --best-effort is set.
+	FailedSections []string `json:"failed_sections,omitempty" yaml:"failed_sections,omitempty" toml:"failed_sections,omitempty" xml:"failed_sections>section,omitempty"`
+	// BaselineDiff is set when --baseline compared this export with a saved result.
+	BaselineDiff *baselineDiff `json:"baseline_diff,omitempty" yaml:"baseline_diff,omitempty" toml:"baseline_diff,omitempty" xml:"baseline_diff,omitempty"`
+	// PinnedViolations are the casks and formulae that miss their --pinned version.
+	PinnedViolations []pinnedViolation `json:"pinned_violations,omitempty" yaml:"pinned_violations,omitempty" toml:"pinned_violations,omitempty" xml:"pinned_violations>violation,omitempty"`
+	// MultiVersionFormulae are the formulae with more than one version installed (counted
+	// by Stats.MultiVersionFormulaCount), which `brew cleanup` would trim; ReclaimableBytes,
+	// set with --with-sizes, is the total size of their older versions.
+	MultiVersionFormulae []multiVersionFormula `json:"multi_version_formulae,omitempty" yaml:"multi_version_formulae,omitempty" toml:"multi_version_formulae,omitempty" xml:"multi_version_formulae>formula,omitempty"`
+	ReclaimableBytes     int64                 `json:"reclaimable_bytes,omitempty" yaml:"reclaimable_bytes,omitempty" toml:"reclaimable_bytes,omitempty" xml:"reclaimable_bytes,omitempty"`
+	// BrewEnv holds the HOMEBREW_* environment variables, with credentials masked.
+	BrewEnv stringMap `json:"brew_env,omitempty" yaml:"brew_env,omitempty" toml:"brew_env,omitempty" xml:"brew_env,omitempty"`
+	// BrewConfig holds the `brew config` lines as key-value pairs.
+	BrewConfig stringMap `json:"brew_config,omitempty" yaml:"brew_config,omitempty" toml:"brew_config,omitempty" xml:"brew_config,omitempty"`
+	// BrewAnalyticsEnabled is reported by `brew analytics state`. The check is part of
+	// the brew-env section, so compact runs leave it false.
+	BrewAnalyticsEnabled bool `json:"brew_analytics_enabled,omitempty" yaml:"brew_analytics_enabled,omitempty" toml:"brew_analytics_enabled,omitempty" xml:"brew_analytics_enabled,omitempty"`
+	// SectionTimings records seconds spent in each major step, keyed by step name.
+	SectionTimings sectionTimings `json:"section_timings,omitempty" yaml:"section_timings,omitempty" toml:"section_timings,omitempty" xml:"section_timings,omitempty"`
 }
 
+// defaultMdfindQuery finds every application bundle Spotlight has indexed.
+const defaultMdfindQuery = "kMDItemContentType == 'com.apple.application-bundle'"
+
+// reportFilePrefix starts every default report name, which lets housekeeping match
+// only files this tool produced.
+const reportFilePrefix = "mac_installed_software_"
+
 type exportOptions struct {
 	reportPath string
 	jsonPath   string
-	compact    bool
+	outputDir  string
+	// noLatestLink skips pointing the latestReportName symlink in outputDir at the new
+	// report.
+	noLatestLink bool
+	// keepReports, when positive, limits how many timestamped reports stay in outputDir.
+	keepReports int
+	cacheDir    string
+	nameFilter  nameFilter
+	// manifestApps adds app bundle names to the manifest hash.
+	manifestApps bool
+	compact      bool
+	verbose      bool
+	withArch     bool
+	leavesOnly   bool
+	rawCaskroom  bool
+	// caskroomDepth limits how deep the --raw-caskroom walk goes.
+	caskroomDepth int
+	// followLinks lets the --raw-caskroom walk descend into symlinked directories.
+	followLinks bool
+	noBrew      bool
+	// appExcludes drops app bundles matched by --exclude-file patterns.
+	appExcludes ignoreRules
+	// systemApps lists the directories whose app bundles are dropped
+	// (--exclude-system-apps); empty keeps everything.
+	systemApps []string
+	// withMetadata reads version, kind, and Finder tags for each app from Spotlight.
+	withMetadata bool
+	// withQuarantine flags apps carrying the com.apple.quarantine xattr.
+	withQuarantine bool
+	// concurrency is how many enrichment tasks (arch, sizes, quarantine, metadata) run
+	// at once.
+	concurrency int
+	// strict makes a missing or empty Applications folder, app list, or --app-dir an
+	// error instead of a zero count or warning.
+	strict bool
+	// mergeApps lists an app found in both Applications folders once, keeping the
+	// /Applications copy (--merge-user-and-system-apps).
+	mergeApps bool
+	// maxApps caps the app list after sorting (0 is unlimited).
+	maxApps int
+	// noSort keeps apps, casks, and formulae in the order the underlying commands
+	// returned them (--no-sort).
+	noSort bool
+	// appDirs are extra directories searched for app bundles (--app-dir).
+	appDirs []string
+	// resolveSymlinks shows where symlinked entries in the Applications folders point.
+	resolveSymlinks bool
+	// minVersionAge flags formulae at least this many minor versions behind stable (0 is off).
+	minVersionAge int
+	// jsonOnly writes only the brew JSON, counting casks and formulae but skipping the
+	// text report (--json-only-metadata).
+	jsonOnly bool
+	// summaryOnly runs discovery for the counts alone and writes no files: no report,
+	// no brew JSON (--summary-only).
+	summaryOnly bool
+	// reportTemplate, when set, replaces the built-in text report (--template).
+	reportTemplate *template.Template
+	// appendReport adds this run to the end of an existing report instead of replacing it.
+	appendReport bool
+	// emit, when set, receives each section's items as it finishes (--output jsonl).
+	emit sectionEmitter
+	// collectors are external executables whose NDJSON output becomes a report section.
+	collectors []string
+	// mdfindQuery and mdfindOnlyIn control Spotlight app discovery.
+	mdfindQuery  string
+	mdfindOnlyIn string
+	// noDoctor and noBrewConfig drop the slow brew doctor and brew config steps
+	// without the rest of --compact.
+	noDoctor     bool
+	noBrewConfig bool
+	// brewPrefix overrides `brew --prefix` (from --brew-prefix or HOMEBREW_PREFIX).
+	brewPrefix string
+	// baseline, when set, is the saved result the baseline section diffs against.
+	baseline     *exportResult
+	baselinePath string
+	// pinned, when set, holds the --pinned versions the pinned section checks.
+	pinned     *pinnedManifest
+	pinnedPath string
+	// relativeTo, when set, is an absolute directory that report paths are shown
+	// relative to.
+	relativeTo string
+	// dependencyGraph writes a DOT file of formula dependencies next to the brew JSON.
+	dependencyGraph bool
+	// packageTables lays out the cask and formula lists as aligned name/version
+	// columns (--report-format table).
+	packageTables bool
+	// groupByTap lists formulae under a subheading per tap (--group-by-tap).
+	groupByTap bool
+	// withSizes measures app bundle sizes for the largest-apps list.
+	withSizes bool
+	// appTree prints app bundles grouped by directory instead of as a flat list.
+	appTree bool
+	// verifyBrewJSON re-reads the brew JSON after writing it and retries once if corrupt.
+	verifyBrewJSON bool
+	// bestEffort records section failures as warnings and keeps exporting.
+	bestEffort bool
+	// redactHome rewrites the home directory to ~ in the report and rendered result.
+	redactHome bool
+	sections   []string
+	// quietErrors downgrades failures in optional sections (login items, Caskroom
+	// walk, brew config, brew doctor, brew JSON) to warnings.
+	quietErrors bool
+}
+
+// mdfindArgs builds the mdfind arguments for app discovery.
+func (opts exportOptions) mdfindArgs() []string {
+	query := opts.mdfindQuery
+	if query == "" {
+		query = defaultMdfindQuery
+	}
+	if opts.mdfindOnlyIn != "" {
+		return []string{"-onlyin", opts.mdfindOnlyIn, query}
+	}
+	return []string{query}
+}
+
+// summaryOnlyConflicts are the flags that ask for a file write, which --summary-only
+// rules out.
+var summaryOnlyConflicts = []string{
+	"output-file", "output-dir", "brew-json-file", "append", "keep", "cache-dir",
+	"include-dependencies-graph", "template", "json-only-metadata", "json-summary-file",
+	"warnings-file", "sqlite",
 }
 
 func exportCmd() *cobra.Command {
-	defaultReport := fmt.Sprintf("mac_installed_software_%s.txt", time.Now().Format("2006-01-02_15-04-05"))
+	defaultReport := fmt.Sprintf("%s%s.txt", reportFilePrefix, time.Now().Format("2006-01-02_15-04-05"))
 	defaultJSON := "brew_installed.json"
 
 	var (
-		opts       output.OutputOptions
-		reportPath = defaultReport
-		jsonPath   = defaultJSON
-		compact    bool
+		opts        output.OutputOptions
+		reportPath  = defaultReport
+		jsonPath    = defaultJSON
+		compact     bool
+		verbose     bool
+		quietErrs   bool
+		withArch    bool
+		sections    string
+		s3URI       string
+		webhookURL  string
+		outputDir   string
+		failOnWarn  bool
+		cacheDir    string
+		nameGlob    string
+		nameSkip    string
+		hashApps    bool
+		leavesOnly  bool
+		watch       bool
+		watchEvery  time.Duration
+		keep        int
+		rawCask     bool
+		noBrew      bool
+		appendRun   bool
+		resolveLink bool
+		minVerAge   int
+		jsonOnly    bool
+		summaryOnly bool
+		tmplPath    string
+		appDirs     []string
+		noSort      bool
+		warnFile    string
+		sqlitePath  string
+		skipNoWarn  bool
+		maxApps     int
+		withMeta    bool
+		quarantine  bool
+		mergeApps   bool
+		usePager    bool
+		strict      bool
+		redactHome  bool
+		compactJSON bool
+		brewPrefix  string
+		bestEffort  bool
+		verifyJSON  bool
+		appTree     bool
+		baseline    string
+		pinnedPath  string
+		profile     string
+		relativeTo  string
+		noDoctor    bool
+		noConfig    bool
+		mdQuery     string
+		mdOnlyIn    string
+		quietFmt    string
+		collectors  []string
+		excludeFile []string
+		withSizes   bool
+		jsonSummary string
+		depsGraph   bool
+		caskDepth   int
+		followLinks bool
+		concurrency int
+		noLatest    bool
+		reportFmt   string
+		groupByTap  bool
+		excludeSys  bool
+		sysPrefixes []string
+		repeat      int
 	)
 
 	cmd := &cobra.Command{
@@ -86,6 +382,16 @@ func exportCmd() *cobra.Command {
 		Long: strings.TrimSpace(`
 Export a full inventory of installed macOS apps, Homebrew casks (GUI), formulae (CLI),
 and Homebrew metadata. Outputs a text report plus a JSON file from 'brew info --installed --json=v2'.
+
+Spotlight app discovery and the brew cask/formula lists are required and always fail the
+export. With --quiet-errors, failures in the optional sections (login items, cask paths/Caskroom walk,
+brew config, brew doctor, brew JSON) are recorded as warnings and the export exits 0.
+
+When a section fails, the sections already written are saved with an incomplete footer.
+With --best-effort, the failure is recorded as a warning and the remaining sections still run.
+
+Exit codes: 0 success, 1 failure, 2 unsupported OS (not macOS), 3 required command missing
+(mdfind, brew), 4 warnings recorded under --fail-on-warnings.
 `),
 		Example: strings.TrimSpace(`
 Example:
@@ -96,260 +402,953 @@ Example:
   # Write reports to a custom directory
   arc-apps export --output-file ~/Desktop/mac_apps.txt --brew-json-file ~/Desktop/brew.json
 
+Example:
+  # Drop the timestamped report and brew_installed.json into one directory
+  arc-apps export --output-dir ~/Desktop/inventory
+
+Example:
+  # Keep only the ten most recent timestamped reports in that directory
+  arc-apps export --output-dir ~/Desktop/inventory --keep 10
+
 Example:
   # Emit a JSON summary for scripting while still writing files
   arc-apps export --output json
 
+Example:
+  # Emit the summary as TOML ([stats] becomes its own table)
+  arc-apps export --output toml
+
+Example:
+  # Emit an <export> XML document for legacy inventory imports
+  arc-apps export --output xml
+
 Example:
   # Keep quiet output for cronjobs
   arc-apps export --output quiet
 
 Example:
-  # Compact run (skip brew doctor/config and brew JSON)
+  # Only formulae and casks, formulae first
+  arc-apps export --sections formulae,casks
+
+Example:
+  # Upload the finished files to S3 using the standard AWS credential chain
+  arc-apps export --s3 s3://fleet-inventory/macs
+
+Example:
+  # Fail CI when brew doctor or any optional step reports a problem
+  arc-apps export --fail-on-warnings --output quiet
+
+Example:
+  # Skip regenerating the brew JSON when nothing was installed or upgraded
+  arc-apps export --cache-dir ~/.cache/arc-apps
+
+Example:
+  # Focus a security review on OpenSSL and Python formulae
+  arc-apps export --name-filter '^(openssl|python@)' --sections casks,formulae
+
+Example:
+  # Keep the inventory fresh, re-exporting only when something changes
+  arc-apps export --watch --watch-interval 30m --output-dir ~/inventory
+
+Example:
+  # App inventory on a locked-down Mac without Homebrew
+  arc-apps export --no-brew
+
+Example:
+  # Share a report without exposing your username
+  arc-apps export --redact-home
+
+Example:
+  # Minified JSON summary for piping into another program
+  arc-apps export --output json --compact-json | jq .stats
+
+Example:
+  # Drift check against a saved baseline
+  arc-apps export --output json > baseline.json
+  arc-apps export --baseline baseline.json
+
+Example:
+  # Security-focused audit preset (explicit flags still override it)
+  arc-apps export --profile security
+
+Example:
+  # Keep the brew JSON but skip the slow brew doctor
+  arc-apps export --no-doctor
+
+Example:
+  # Audit only one directory, with a custom Spotlight query
+  arc-apps export --mdfind-onlyin ~/Applications --mdfind-query "kMDItemKind == 'Application'"
+
+Example:
+  # Merge items from an organization-specific inventory tool
+  arc-apps export --collector ./mdm-inventory
+
+Example:
+  # Skip app bundles listed in a gitignore-style file
+  arc-apps export --exclude-file ~/.config/arc-apps/exclude
+
+Example:
+  # Name the report after the machine and date
+  arc-apps export --output-file "~/inventory/{hostname}-{date}.txt"
+
+Example:
+  # Human summary on screen plus machine-readable JSON on disk
+  arc-apps export --json-summary-file ~/inventory/summary.json
+
+Example:
+  # Graph formula dependencies (render with: dot -Tpng brew_installed.dot -o deps.png)
+  arc-apps export --include-dependencies-graph
+
+Example:
+  # Stream one JSON line per section as it finishes, then the full result
+  arc-apps export --output jsonl | jq -c 'select(.section == "casks")'
+
+Example:
+  # Stitch a sudo run and a user run into one report
+  sudo arc-apps export --output-file ~/inventory/full.txt
+  arc-apps export --output-file ~/inventory/full.txt --append
+
+Example:
+  # See which /Applications entries are symlinks and where they lead
+  arc-apps export --resolve-symlinks
+
+Example:
+  # Plan upgrades: formulae two or more minor versions behind stable
+  arc-apps export --min-version-age 2
+
+Example:
+  # Metadata-only ingestion: brew JSON and counts, no text report
+  arc-apps export --json-only-metadata --output json
+
+Example:
+  # Custom report layout from a Go text/template
+  #   {{range .Casks}}{{.Name}} {{join .Versions ", "}}{{"\n"}}{{end}}
+  arc-apps export --template ~/.config/arc-apps/report.tmpl
+
+Example:
+  # Include portable apps and an external volume
+  arc-apps export --app-dir ~/bin/apps --app-dir /Volumes/Tools/Applications
+
+Example:
+  # Fold Spotlight version, kind, and Finder tags into the app list
+  arc-apps export --with-metadata
+
+Example:
+  # Collect warnings for a log pipeline, separately from the main output
+  arc-apps export --output quiet --warnings-file ~/inventory/warnings.jsonl
+
+Example:
+  # Keep a longitudinal history; query it with sqlite3 afterwards
+  arc-apps export --sqlite ~/inventory/history.db
+
+Example:
+  # Quick "what do I have" glance; nothing is written to disk
+  arc-apps export --summary-only
+
+Example:
+  # CI: fail on a broken environment instead of reporting zero apps
+  arc-apps export --strict --output quiet
+
+Example:
+  # Fleet compliance: fail when casks or formulae drift from approved versions
+  arc-apps export --pinned pinned.yaml --fail-on-warnings
+
+Example:
+  # Compact run (skip login items, brew doctor/config, and brew JSON)
   arc-apps export --compact --output-file ~/Desktop/apps_compact.txt
 `),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if runtime.GOOS != "darwin" {
-				return &arcer.CLIError{
+				return withExitCode(exitUnsupportedOS, &arcer.CLIError{
 					Msg:  "arc-apps export currently supports macOS only",
 					Hint: "This command wraps Spotlight (mdfind) and Homebrew. Run from macOS where these tools exist.",
+				})
+			}
+
+			if profile != "" {
+				if err := applyProfile(cmd, profile); err != nil {
+					return err
+				}
+			}
+
+			format := localFormat(cmd)
+			if format == "" {
+				if err := opts.Resolve(); err != nil {
+					return err
+				}
+			}
+
+			sectionList, err := parseSections(sections)
+			if err != nil {
+				return err
+			}
+			var s3Dest *s3Target
+			if s3URI != "" {
+				target, err := parseS3URI(s3URI)
+				if err != nil {
+					return err
 				}
+				s3Dest = &target
+			}
+			if webhookURL != "" {
+				if err := validateWebhookURL(webhookURL); err != nil {
+					return err
+				}
+			}
+			// Naming the arch section explicitly is enough to enable it.
+			if cmd.Flags().Changed("sections") && slices.Contains(sectionList, "arch") {
+				withArch = true
 			}
 
-			if err := opts.Resolve(); err != nil {
+			if keep < 0 || (keep > 0 && outputDir == "") {
+				return &arcer.CLIError{
+					Msg:  "--keep requires --output-dir and a non-negative count",
+					Hint: "Retention only prunes timestamped reports inside --output-dir.",
+					Suggestions: []string{
+						"arc-apps export --output-dir ~/inventory --keep 10",
+					},
+				}
+			}
+			now := time.Now()
+			if reportPath, err = expandPathTemplate("--output-file", reportPath, now); err != nil {
+				return err
+			}
+			if jsonSummary, err = expandPathTemplate("--json-summary-file", jsonSummary, now); err != nil {
+				return err
+			}
+			if jsonSummary != "" {
+				jsonSummary = utils.ExpandPath(jsonSummary)
+			}
+			if warnFile, err = expandPathTemplate("--warnings-file", warnFile, now); err != nil {
+				return err
+			}
+			if sqlitePath, err = expandPathTemplate("--sqlite", sqlitePath, now); err != nil {
+				return err
+			}
+			if sqlitePath != "" {
+				sqlitePath = utils.ExpandPath(sqlitePath)
+				if err := ensureCommand("sqlite3", "--sqlite drives the sqlite3 CLI, which ships with macOS; install it or drop the flag."); err != nil {
+					return err
+				}
+			}
+			if jsonPath, err = expandPathTemplate("--brew-json-file", jsonPath, now); err != nil {
 				return err
 			}
 
-			expOpts := exportOptions{
-				reportPath: utils.ExpandPath(reportPath),
-				jsonPath:   utils.ExpandPath(jsonPath),
-				compact:    compact,
+			if summaryOnly {
+				for _, name := range summaryOnlyConflicts {
+					if cmd.Flags().Changed(name) {
+						return &arcer.CLIError{
+							Msg:  "--summary-only cannot be combined with --" + name,
+							Hint: "--summary-only writes no files; drop one of the two flags.",
+						}
+					}
+				}
+			}
+			if outputDir != "" {
+				outputDir = utils.ExpandPath(outputDir)
+				if err := os.MkdirAll(outputDir, 0o755); err != nil {
+					return err
+				}
+				// Canonical names inside the directory, unless a path was given explicitly.
+				if !cmd.Flags().Changed("output-file") {
+					reportPath = filepath.Join(outputDir, defaultReport)
+				}
+				if !cmd.Flags().Changed("brew-json-file") {
+					jsonPath = filepath.Join(outputDir, defaultJSON)
+				}
 			}
 
-			result, err := runExport(cmd.Context(), expOpts)
+			filter, err := newNameFilter(nameGlob, nameSkip)
 			if err != nil {
 				return err
 			}
+			if cacheDir != "" {
+				cacheDir = utils.ExpandPath(cacheDir)
+			}
+			if brewPrefix == "" {
+				brewPrefix = os.Getenv("HOMEBREW_PREFIX")
+			}
+			if brewPrefix != "" {
+				brewPrefix = filepath.Clean(utils.ExpandPath(brewPrefix))
+			}
 
-			switch {
-			case opts.Is(output.OutputJSON):
-				enc := jsonEncoder(cmd.OutOrStdout())
-				return enc.Encode(result)
-			case opts.Is(output.OutputYAML):
-				enc := yamlEncoder(cmd.OutOrStdout())
-				return enc.Encode(result)
-			case opts.Is(output.OutputQuiet):
-				fmt.Fprintln(cmd.OutOrStdout(), result.ReportPath)
-				fmt.Fprintln(cmd.OutOrStdout(), result.BrewJSONPath)
-				return nil
+			if relativeTo != "" {
+				if relativeTo, err = filepath.Abs(utils.ExpandPath(relativeTo)); err != nil {
+					return err
+				}
+			}
+
+			if strings.TrimSpace(mdQuery) == "" {
+				return &arcer.CLIError{
+					Msg:  "--mdfind-query must not be empty",
+					Hint: "Omit the flag to use the default query: " + defaultMdfindQuery,
+				}
+			}
+			if mdOnlyIn != "" {
+				mdOnlyIn = utils.ExpandPath(mdOnlyIn)
+				if info, err := os.Stat(mdOnlyIn); err != nil || !info.IsDir() {
+					return &arcer.CLIError{
+						Msg:  fmt.Sprintf("--mdfind-onlyin %s is not a directory", mdOnlyIn),
+						Hint: "Pass an existing directory such as /Applications.",
+					}
+				}
+			}
+
+			if caskDepth < 0 {
+				return &arcer.CLIError{
+					Msg:  fmt.Sprintf("--caskroom-depth must be 0 or more, got %d", caskDepth),
+					Hint: fmt.Sprintf("The default of %d lists each cask and its version folders.", defaultCaskroomDepth),
+				}
+			}
+			if jsonOnly {
+				var conflict string
+				switch {
+				case compact:
+					conflict = "--compact"
+				case noBrew:
+					conflict = "--no-brew"
+				case appendRun:
+					conflict = "--append"
+				case cmd.Flags().Changed("sections"):
+					conflict = "--sections"
+				case tmplPath != "":
+					conflict = "--template"
+				}
+				if conflict != "" {
+					return &arcer.CLIError{
+						Msg:  "--json-only-metadata cannot be combined with " + conflict,
+						Hint: "--json-only-metadata writes only the brew JSON; drop one of the two flags.",
+					}
+				}
+			}
+			var reportTmpl *template.Template
+			if tmplPath != "" {
+				if reportTmpl, err = loadReportTemplate(utils.ExpandPath(tmplPath)); err != nil {
+					return err
+				}
+			}
+			if maxApps < 0 {
+				return &arcer.CLIError{
+					Msg:  fmt.Sprintf("--max-apps must be 0 or more, got %d", maxApps),
+					Hint: "Use 0 (the default) for no limit.",
+				}
+			}
+			if minVerAge < 0 {
+				return &arcer.CLIError{
+					Msg:  fmt.Sprintf("--min-version-age must be 0 or more, got %d", minVerAge),
+					Hint: "Pass the number of minor versions a formula may trail stable, e.g. --min-version-age 2.",
+				}
+			}
+			switch reportFmt {
+			case reportRaw, reportTable:
 			default:
-				printSummary(cmd.OutOrStdout(), result)
-				return nil
+				return &arcer.CLIError{
+					Msg:  fmt.Sprintf("unknown --report-format %q", reportFmt),
+					Hint: "Use raw (`name version` lines) or table (aligned columns).",
+				}
+			}
+			if repeat < 0 {
+				return &arcer.CLIError{
+					Msg:  fmt.Sprintf("--repeat must be 0 or more, got %d", repeat),
+					Hint: "Pass the number of back-to-back exports to time, e.g. --repeat 5.",
+				}
+			}
+			if concurrency < 1 {
+				return &arcer.CLIError{
+					Msg:  fmt.Sprintf("--concurrency must be 1 or more, got %d", concurrency),
+					Hint: "Use 1 to run enrichment passes one at a time.",
+				}
+			}
+			if collectors, err = resolveCollectors(collectors); err != nil {
+				return err
+			}
+			for i, dir := range appDirs {
+				if appDirs[i], err = filepath.Abs(utils.ExpandPath(dir)); err != nil {
+					return err
+				}
+			}
+			appExcludes, err := loadIgnoreFiles(excludeFile)
+			if err != nil {
+				return err
 			}
-		},
-	}
 
-	cmd.Flags().StringVarP(&reportPath, "output-file", "f", reportPath, "Path for the text report (default includes timestamp)")
-	cmd.Flags().StringVar(&jsonPath, "brew-json-file", jsonPath, "Path for the Homebrew JSON metadata output")
-	cmd.Flags().BoolVar(&compact, "compact", false, "Skip brew doctor/config output and brew JSON (faster, smaller)")
-	opts.AddOutputFlags(cmd, output.OutputTable)
-	return cmd
-}
+			var pinned *pinnedManifest
+			if pinnedPath != "" {
+				pinnedPath = utils.ExpandPath(pinnedPath)
+				if pinned, err = loadPinnedManifest(pinnedPath); err != nil {
+					return err
+				}
+			}
+			var baselineResult *exportResult
+			if baseline != "" {
+				baseline = utils.ExpandPath(baseline)
+				if baselineResult, err = loadBaseline(baseline); err != nil {
+					return err
+				}
+			}
 
-func runExport(ctx context.Context, opts exportOptions) (exportResult, error) {
-	var result exportResult
+			expOpts := exportOptions{
+				reportPath:      utils.ExpandPath(reportPath),
+				jsonPath:        utils.ExpandPath(jsonPath),
+				outputDir:       outputDir,
+				noLatestLink:    noLatest,
+				keepReports:     keep,
+				cacheDir:        cacheDir,
+				nameFilter:      filter,
+				manifestApps:    hashApps,
+				leavesOnly:      leavesOnly,
+				rawCaskroom:     rawCask,
+				noBrew:          noBrew,
+				appendReport:    appendRun,
+				resolveSymlinks: resolveLink,
+				minVersionAge:   minVerAge,
+				jsonOnly:        jsonOnly,
+				summaryOnly:     summaryOnly,
+				reportTemplate:  reportTmpl,
+				caskroomDepth:   caskDepth,
+				followLinks:     followLinks,
+				dependencyGraph: depsGraph,
+				withSizes:       withSizes,
+				packageTables:   reportFmt == reportTable,
+				groupByTap:      groupByTap,
+				appExcludes:     appExcludes,
+				systemApps:      systemAppPrefixes(excludeSys, sysPrefixes),
+				appDirs:         appDirs,
+				noSort:          noSort,
+				maxApps:         maxApps,
+				withMetadata:    withMeta,
+				withQuarantine:  quarantine,
+				concurrency:     concurrency,
+				mergeApps:       mergeApps,
+				strict:          strict,
+				collectors:      collectors,
+				mdfindQuery:     mdQuery,
+				mdfindOnlyIn:    mdOnlyIn,
+				noDoctor:        noDoctor,
+				noBrewConfig:    noConfig,
+				brewPrefix:      brewPrefix,
+				redactHome:      redactHome,
+				bestEffort:      bestEffort,
+				verifyBrewJSON:  verifyJSON,
+				appTree:         appTree,
+				relativeTo:      relativeTo,
+				baseline:        baselineResult,
+				baselinePath:    baseline,
+				pinned:          pinned,
+				pinnedPath:      pinnedPath,
+				compact:         compact,
+				verbose:         verbose,
+				quietErrors:     quietErrs,
+				withArch:        withArch,
+				sections:        sectionList,
+			}
 
-	if err := ensureCommand("mdfind", "Spotlight CLI missing. Ensure you're on macOS with Spotlight enabled."); err != nil {
-		return result, err
-	}
-	if err := ensureCommand("brew", "Install Homebrew from https://brew.sh/ to capture casks and formulae."); err != nil {
-		return result, err
-	}
+			switch quietFmt {
+			case quietPaths, quietTSV:
+			default:
+				return &arcer.CLIError{
+					Msg:  fmt.Sprintf("unknown --quiet-format %q", quietFmt),
+					Hint: "Use paths (report and brew JSON paths) or tsv (" + quietTSVFields + ").",
+				}
+			}
+			render := renderOptions{output: opts, format: format, compactJSON: compactJSON, quietFormat: quietFmt}
 
-	absReport, err := filepath.Abs(opts.reportPath)
-	if err != nil {
-		return result, err
-	}
-	absJSON, err := filepath.Abs(opts.jsonPath)
-	if err != nil {
-		return result, err
-	}
+			var redactor homeRedactor
+			if redactHome {
+				home, _ := os.UserHomeDir()
+				redactor = newHomeRedactor(home)
+			}
 
-	if err := os.MkdirAll(filepath.Dir(absReport), 0o755); err != nil {
-		return result, err
-	}
-	if err := os.MkdirAll(filepath.Dir(absJSON), 0o755); err != nil {
-		return result, err
-	}
+			if format == formatJSONL {
+				rewrite := func(s string) string { return s }
+				if redactHome {
+					rewrite = redactor.redact
+				}
+				expOpts.emit = newJSONLEmitter(cmd.OutOrStdout(), rewrite)
+			}
 
-	reportFile, err := os.Create(absReport)
-	if err != nil {
-		return result, err
-	}
-	defer reportFile.Close()
+			// publish runs the post-export hooks that send results elsewhere. Uploads and
+			// the --sqlite history need the real paths, so home redaction happens after them.
+			publish := func(result exportResult) exportResult {
+				if sqlitePath != "" {
+					if _, err := appendSQLite(cmd.Context(), sqlitePath, result); err != nil {
+						result.Warnings = append(result.Warnings, fmt.Sprintf("--sqlite %s not updated: %v", sqlitePath, err))
+					}
+				}
+				if s3Dest != nil {
+					uris, warnings := uploadExport(cmd.Context(), *s3Dest, result)
+					result.UploadedURIs = uris
+					result.Warnings = append(result.Warnings, warnings...)
+				}
+				result = redactor.redactResult(result)
+				if webhookURL != "" {
+					if warn := postWebhook(cmd.Context(), webhookURL, result); warn != "" {
+						result.Warnings = append(result.Warnings, warn)
+					}
+				}
+				return result
+			}
 
-	writer := bufio.NewWriter(reportFile)
-	defer writer.Flush()
+			// emit publishes result, saves --json-summary-file and --warnings-file, and
+			// prints the result.
+			emit := func(result exportResult) (exportResult, error) {
+				result = publish(result)
+				if jsonSummary != "" {
+					if err := writeJSONFile(jsonSummary, result); err != nil {
+						return result, err
+					}
+				}
+				if warnFile != "" && (len(result.Warnings) > 0 || !skipNoWarn) {
+					if err := writeWarningsFile(warnFile, result); err != nil {
+						return result, err
+					}
+				}
+				return result, renderPaged(cmd.OutOrStdout(), render, result, usePager && !watch)
+			}
 
-	result.ReportPath = absReport
-	if !opts.compact {
-		result.BrewJSONPath = absJSON
-	}
-	result.StartedAt = time.Now()
+			if repeat > 0 {
+				if watch {
+					return &arcer.CLIError{
+						Msg:  "--repeat and --watch cannot be combined",
+						Hint: "--repeat is for timing back-to-back exports; drop --watch.",
+					}
+				}
+				return runRepeat(cmd.Context(), expOpts, repeat, cmd.OutOrStdout())
+			}
 
-	stats := exportStats{}
+			if watch {
+				if watchEvery <= 0 {
+					return &arcer.CLIError{
+						Msg:  fmt.Sprintf("--watch-interval must be positive, got %s", watchEvery),
+						Hint: "Use a Go duration such as 10m or 1h.",
+					}
+				}
+				return runWatch(cmd.Context(), expOpts, watchEvery, cmd.ErrOrStderr(), func(result exportResult) error {
+					_, err := emit(result)
+					return err
+				})
+			}
 
-	if err := writeSectionHeader(writer, "MAC SYSTEM + USER INSTALLED APPLICATIONS (.app bundles)"); err != nil {
-		return result, err
+			result, err := runExport(cmd.Context(), expOpts)
+			if err != nil {
+				if !result.CompletedAt.IsZero() && result.ReportPath != "" {
+					fmt.Fprintf(cmd.ErrOrStderr(), "Partial report saved: %s\n", result.ReportPath)
+				}
+				return err
+			}
+			if result, err = emit(result); err != nil {
+				return err
+			}
+			if failOnWarn && len(result.Warnings) > 0 {
+				return warningsError(result.Warnings)
+			}
+			return nil
+		},
 	}
 
-	appBundles, err := commandLines(ctx, "mdfind", "kMDItemContentType == 'com.apple.application-bundle'")
-	if err != nil {
-		return result, wrapCommandErr("mdfind", err, "")
-	}
-	sort.Strings(appBundles)
-	stats.AppBundleCount = len(appBundles)
-	if err := writeLines(writer, appBundles); err != nil {
-		return result, err
-	}
+	cmd.Flags().StringVarP(&reportPath, "output-file", "f", reportPath, "Path for the text report (default includes timestamp; placeholders: "+pathPlaceholders+")")
+	cmd.Flags().BoolVar(&appendRun, "append", false, "Add this run to the end of an existing --output-file (after a timestamped separator) instead of overwriting it")
+	cmd.Flags().StringVar(&jsonPath, "brew-json-file", jsonPath, "Path for the Homebrew JSON metadata output (supports the --output-file placeholders)")
+	cmd.Flags().StringVar(&tmplPath, "template", "", "Render the text report from this Go text/template `file` instead of the built-in layout")
+	cmd.Flags().BoolVar(&summaryOnly, "summary-only", false, "Print the counts (or the --output result) without writing the report, brew JSON, or any other file")
+	cmd.Flags().BoolVar(&jsonOnly, "json-only-metadata", false, "Write only the brew JSON (plus cask/formula counts) and skip the text report")
+	cmd.Flags().BoolVar(&verifyJSON, "verify-brew-json", false, "Check that the brew JSON parses after writing it, re-running brew info once if it doesn't")
+	cmd.Flags().StringVar(&jsonSummary, "json-summary-file", "", "Also write the structured result as JSON to this `path`, keeping the human summary on stdout")
+	cmd.Flags().StringVar(&sqlitePath, "sqlite", "", "Append this run and its items to a SQLite history database at `path`, creating or migrating its schema as needed (supports the --output-file placeholders)")
+	cmd.Flags().StringVar(&warnFile, "warnings-file", "", "Also write the warnings as JSON lines ({hostname, started_at, message}) to this `path` (supports the --output-file placeholders)")
+	cmd.Flags().BoolVar(&skipNoWarn, "warnings-file-skip-empty", false, "With --warnings-file, leave the file untouched when there are no warnings instead of truncating it")
+	cmd.Flags().StringVar(&outputDir, "output-dir", "", "Directory for all outputs with canonical names (overridden by --output-file/--brew-json-file)")
+	cmd.Flags().BoolVar(&noLatest, "no-latest-symlink", false, "With --output-dir, don't point "+latestReportName+" at the report just written")
+	cmd.Flags().IntVar(&keep, "keep", 0, "With --output-dir, keep only the newest N timestamped reports (0 keeps all)")
+	cmd.Flags().StringVar(&mdQuery, "mdfind-query", defaultMdfindQuery, "Spotlight query used to discover app bundles")
+	cmd.Flags().StringVar(&mdOnlyIn, "mdfind-onlyin", "", "Limit Spotlight app discovery to this `dir` (mdfind -onlyin)")
+	cmd.Flags().StringVar(&cacheDir, "cache-dir", "", "Reuse a cached brew JSON when the installed cask/formula set is unchanged")
+	cmd.Flags().StringVar(&nameGlob, "name-filter", "", "Only include casks/formulae whose name matches this glob (openssl*) or regex")
+	cmd.Flags().StringVar(&nameSkip, "name-exclude", "", "Exclude casks/formulae whose name matches this glob or regex")
+	cmd.Flags().StringArrayVar(&appDirs, "app-dir", nil, "Also search this `dir` for app bundles and merge them into the apps section (repeatable)")
+	cmd.Flags().BoolVar(&excludeSys, "exclude-system-apps", false, "Drop app bundles under the --system-app-prefix directories (default /System) from the apps section and counts")
+	cmd.Flags().StringArrayVar(&sysPrefixes, "system-app-prefix", defaultSystemAppPrefixes, "With --exclude-system-apps, a `dir` whose app bundles are excluded (repeatable; replaces the default, e.g. --system-app-prefix /System --system-app-prefix /Library)")
+	cmd.Flags().StringArrayVar(&excludeFile, "exclude-file", nil, "Drop app bundles matching the gitignore-style patterns in this `file` (repeatable; !pattern re-includes)")
+	cmd.Flags().BoolVar(&hashApps, "manifest-with-apps", false, "Include app bundle names in the manifest hash alongside casks and formulae")
+	cmd.Flags().BoolVar(&groupByTap, "group-by-tap", false, "List formulae under a subheading per tap (homebrew/core first) with per-tap counts")
+	cmd.Flags().BoolVar(&leavesOnly, "leaves-only", false, "List only formulae you installed on request (brew leaves), not their dependencies")
+	cmd.Flags().BoolVar(&strict, "strict", false, "Fail when /Applications is missing or empty, ~/Applications cannot be listed, mdfind finds no apps, or an --app-dir is unreadable, instead of reporting zeros")
+	cmd.Flags().BoolVar(&usePager, "pager", false, "Show the table summary through $PAGER (default \""+defaultPager+"\") when stdout is a terminal; ignored for structured formats and --watch")
+	cmd.Flags().BoolVar(&watch, "watch", false, "Keep running and re-export whenever the app/cask/formula inventory changes (Ctrl-C to stop)")
+	// --repeat is a measurement aid for performance work, not a user feature.
+	cmd.Flags().IntVar(&repeat, "repeat", 0, "Run the export N times into a temporary directory, discarding the output, and print per-run and aggregate timings")
+	_ = cmd.Flags().MarkHidden("repeat")
+	cmd.Flags().DurationVar(&watchEvery, "watch-interval", 15*time.Minute, "How often --watch re-checks the inventory")
+	cmd.Flags().BoolVar(&rawCask, "raw-caskroom", false, "List Caskroom version folders instead of each cask's installed app path")
+	cmd.Flags().StringVar(&brewPrefix, "brew-prefix", "", "Homebrew `path` to inspect instead of brew --prefix (default: $HOMEBREW_PREFIX)")
+	cmd.Flags().IntVar(&caskDepth, "caskroom-depth", defaultCaskroomDepth, "With --raw-caskroom, how many directory levels below the Caskroom to list")
+	cmd.Flags().BoolVar(&followLinks, "follow-symlinks", false, "With --raw-caskroom, descend into symlinked directories (nonstandard brew layouts); symlink cycles are skipped with a warning")
+	cmd.Flags().BoolVar(&noBrew, "no-brew", false, "Skip every Homebrew section and the brew presence check (apps, launchd, login items, etc. only)")
+	cmd.Flags().StringVar(&relativeTo, "relative-to", "", "Show app and Caskroom paths in the text report relative to this `dir` (paths outside it stay absolute)")
+	cmd.Flags().BoolVar(&redactHome, "redact-home", false, "Replace the home directory with ~ in report paths and structured output")
+	cmd.Flags().BoolVar(&noDoctor, "no-doctor", false, "Skip brew doctor but keep the brew JSON and everything else")
+	cmd.Flags().BoolVar(&noConfig, "no-brew-config", false, "Skip the brew config dump")
+	cmd.Flags().BoolVar(&compact, "compact", false, "Skip login items, Rust tools, brew doctor/config output, and brew JSON (faster, smaller)")
+	cmd.Flags().BoolVar(&bestEffort, "best-effort", false, "Keep exporting when a section fails, recording the failure as a warning")
+	cmd.Flags().BoolVar(&quietErrs, "quiet-errors", false, "Record failures in optional sections (login items, Caskroom, brew config/doctor/JSON) as warnings instead of failing")
+	cmd.Flags().IntVar(&minVerAge, "min-version-age", 0, "List formulae at least N minor versions (or a major version) behind the latest stable, from the brew JSON")
+	cmd.Flags().BoolVar(&depsGraph, "include-dependencies-graph", false, "Write a Graphviz .dot file of formula dependencies next to the brew JSON")
+	cmd.Flags().BoolVar(&mergeApps, "merge-user-and-system-apps", false, "List an app found in both /Applications and ~/Applications once, keeping the /Applications copy and noting the duplicate")
+	cmd.Flags().IntVar(&concurrency, "concurrency", runtime.NumCPU(), "How many enrichment tasks (--with-arch, --with-sizes, --with-quarantine, --with-metadata) run at once; results keep their sorted order")
+	cmd.Flags().BoolVar(&quarantine, "with-quarantine", false, "Flag apps carrying the com.apple.quarantine xattr (downloaded, possibly never opened) with the downloading app and date")
+	cmd.Flags().BoolVar(&withMeta, "with-metadata", false, "Add each app's version, kind, and Finder tags from Spotlight (batched mdls calls)")
+	cmd.Flags().BoolVar(&withSizes, "with-sizes", false, "Measure app bundle sizes and list the "+strconv.Itoa(largestAppLimit)+" largest (walks every bundle), plus the Cellar space old formula versions take")
+	cmd.Flags().BoolVar(&withArch, "with-arch", false, "Tag apps and formulae as arm64, x86_64, or universal (runs lipo on each executable)")
+	cmd.Flags().BoolVar(&resolveLink, "resolve-symlinks", false, "Show the target of symlinked entries in /Applications and ~/Applications, warning on broken links")
+	cmd.Flags().IntVar(&maxApps, "max-apps", 0, "Keep only the first N app bundles (after sorting) and warn about the rest; guards against runaway Spotlight indexes (0 is unlimited)")
+	cmd.Flags().BoolVar(&noSort, "no-sort", false, "Keep apps, casks, and formulae in the order mdfind and brew return them (for debugging discovery)")
+	cmd.Flags().BoolVar(&appTree, "tree", false, "Group app bundles by directory in an indented tree instead of a flat list")
+	cmd.Flags().StringVar(&profile, "profile", "", "Preset flags for an audit: "+strings.Join(profileNames(), ", ")+" (explicit flags override)")
+	cmd.Flags().StringVar(&sections, "sections", "", "Comma-separated report sections in output order (default: "+strings.Join(defaultSectionOrder, ",")+")")
+	cmd.Flags().StringArrayVar(&collectors, "collector", nil, "Run this executable and add its NDJSON items ({\"type\",\"name\",\"version\"} per line) to the report (repeatable)")
+	cmd.Flags().StringVar(&pinnedPath, "pinned", "", "Compare installed cask and formula versions with a YAML `file` of approved versions and warn about each mismatch (too old, too new, missing)")
+	cmd.Flags().StringVar(&baseline, "baseline", "", "Compare against a saved --output json result and report what was added or removed since")
+	cmd.Flags().StringVar(&s3URI, "s3", "", "Upload the report and brew JSON to s3://bucket/prefix (keyed by hostname and timestamp)")
+	cmd.Flags().StringVar(&webhookURL, "webhook", "", "POST a JSON summary (hostname, counts, duration, warnings) to this URL after export")
+	cmd.Flags().BoolVar(&failOnWarn, "fail-on-warnings", false, "Exit with code 4 when the export records any warnings (e.g. brew doctor problems)")
+	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Include extra detail such as the program each launchd plist runs")
+	cmd.Flags().StringVar(&reportFmt, "report-format", reportRaw, "Text report cask and formula lists: raw (\"name version\" lines, diff-friendly) or table (aligned columns)")
+	cmd.Flags().StringVar(&quietFmt, "quiet-format", quietPaths, "With --output quiet: paths (one per line) or tsv (one line: "+quietTSVFields+")")
+	cmd.Flags().BoolVar(&compactJSON, "compact-json", false, "Print --output json on a single line without indentation")
+	opts.AddOutputFlags(cmd, output.OutputTable)
+	return cmd
+}
 
-	if _, err := fmt.Fprintln(writer); err != nil {
-		return result, err
+// renderOptions selects how renderResult prints an export result.
+type renderOptions struct {
+	output output.OutputOptions
+	// format names a mode handled locally (see localFormat) and takes precedence
+	// over the SDK output options.
+	format string
+	// compactJSON drops indentation from --output json.
+	compactJSON bool
+	// quietFormat is quietPaths or quietTSV.
+	quietFormat string
+}
+
+// --quiet-format values.
+const (
+	quietPaths = "paths"
+	quietTSV   = "tsv"
+)
+
+// quietTSVFields documents the column order of --quiet-format tsv.
+const quietTSVFields = "hostname, app bundles, casks, formulae, warnings, duration seconds, report path, brew JSON path"
+
+// renderResult writes result in the selected format.
+func renderResult(w io.Writer, ro renderOptions, result exportResult) error {
+	opts := ro.output
+	switch {
+	case ro.format == formatTOML:
+		return tomlEncoder(w).Encode(result)
+	case ro.format == formatXML:
+		return encodeXML(w, result)
+	case ro.format == formatJSONL:
+		return writeJSONLine(w, sectionLine{Section: "result", Result: &result}, nil)
+	case opts.Is(output.OutputJSON):
+		enc := jsonEncoder(w)
+		if ro.compactJSON {
+			enc.SetIndent("", "")
+		}
+		return enc.Encode(result)
+	case opts.Is(output.OutputYAML):
+		enc := yamlEncoder(w)
+		return enc.Encode(result)
+	case opts.Is(output.OutputQuiet) && ro.quietFormat == quietTSV:
+		fields := []string{
+			hostnameOf(result),
+			strconv.Itoa(result.Stats.AppBundleCount),
+			strconv.Itoa(result.Stats.BrewCaskCount),
+			strconv.Itoa(result.Stats.BrewFormulaCount),
+			strconv.Itoa(len(result.Warnings)),
+			strconv.FormatFloat(result.DurationSeconds, 'f', 2, 64),
+			result.ReportPath,
+			result.BrewJSONPath,
+		}
+		_, err := fmt.Fprintln(w, strings.Join(fields, "\t"))
+		return err
+	case opts.Is(output.OutputQuiet):
+		for _, path := range []string{result.ReportPath, result.BrewJSONPath} {
+			if path != "" {
+				fmt.Fprintln(w, path)
+			}
+		}
+		return nil
+	default:
+		printSummary(w, result)
+		return nil
 	}
-	if _, err := fmt.Fprintln(writer, "-- /Applications ---"); err != nil {
-		return result, err
+}
+
+// summaryOutput reports whether ro selects the human table summary rather than a
+// structured format.
+func (ro renderOptions) summaryOutput() bool {
+	opts := ro.output
+	return ro.format == "" && !opts.Is(output.OutputJSON) && !opts.Is(output.OutputYAML) && !opts.Is(output.OutputQuiet)
+}
+
+// renderPaged is renderResult through $PAGER under --pager. Paging applies only to
+// the table summary on a terminal; otherwise the result is written to w directly.
+func renderPaged(w io.Writer, ro renderOptions, result exportResult, usePager bool) error {
+	if !usePager || !ro.summaryOutput() || !isTerminal(w) {
+		return renderResult(w, ro, result)
 	}
-	systemApps, err := listDirSorted("/Applications")
+	p, err := startPager(w)
 	if err != nil {
-		return result, wrapCommandErr("ls /Applications", err, "")
+		return err
 	}
-	stats.ApplicationsDirCount = len(systemApps)
-	if err := writeLines(writer, systemApps); err != nil {
-		return result, err
+	if err := renderResult(p, ro, result); err != nil {
+		p.Close()
+		return err
 	}
+	return p.Close()
+}
 
-	if _, err := fmt.Fprintln(writer); err != nil {
-		return result, err
+// warningsError is returned under --fail-on-warnings once output has been written.
+func warningsError(warnings []string) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "export finished with %d warning(s):", len(warnings))
+	for _, warn := range warnings {
+		fmt.Fprintf(&b, "\n  - %s", warn)
 	}
-	if _, err := fmt.Fprintln(writer, "-- ~/Applications ---"); err != nil {
+	return withExitCode(exitWarningsAsFailure, &arcer.CLIError{
+		Msg:  b.String(),
+		Hint: "Resolve the warnings above (try `brew doctor`) or drop --fail-on-warnings.",
+	})
+}
+
+func runExport(ctx context.Context, opts exportOptions) (exportResult, error) {
+	result := exportResult{SchemaVersion: exportSchemaVersion}
+	// Every subprocess started through ctx is recorded for CommandsRun, so a result can
+	// be reproduced by hand.
+	commands := &commandLog{}
+	ctx = withCommandLog(ctx, commands)
+
+	if err := ensureCommand("mdfind", "Spotlight CLI missing. Ensure you're on macOS with Spotlight enabled."); err != nil {
 		return result, err
 	}
-	homeDir, _ := os.UserHomeDir()
-	userApps, err := listDirSorted(filepath.Join(homeDir, "Applications"))
-	if err == nil {
-		stats.UserApplicationsCount = len(userApps)
-		if err := writeLines(writer, userApps); err != nil {
+	if !opts.noBrew {
+		brew, err := resolveBrew("Install Homebrew from https://brew.sh/ to capture casks and formulae (or pass --no-brew).")
+		if err != nil {
 			return result, err
 		}
+		result.BrewPath = brew
 	}
 
-	if err := writeSectionHeader(writer, "HOMEBREW CASK APPLICATIONS (GUI)"); err != nil {
+	absReport, err := filepath.Abs(opts.reportPath)
+	if err != nil {
 		return result, err
 	}
-	casks, err := commandLines(ctx, "brew", "list", "--cask", "--versions")
+	absJSON, err := filepath.Abs(opts.jsonPath)
 	if err != nil {
-		return result, wrapCommandErr("brew list --cask --versions", err, "Confirm Homebrew is installed and casks are set up.")
-	}
-	sort.Strings(casks)
-	stats.BrewCaskCount = len(casks)
-	if err := writeLines(writer, casks); err != nil {
 		return result, err
 	}
 
-	if !opts.compact {
-		if _, err := fmt.Fprintln(writer); err != nil {
+	if !opts.summaryOnly {
+		if err := os.MkdirAll(filepath.Dir(absJSON), 0o755); err != nil {
 			return result, err
 		}
-		if _, err := fmt.Fprintln(writer, "-- Installed paths --"); err != nil {
+	}
+
+	// Under --json-only-metadata and --summary-only the report text is discarded and
+	// no file is created.
+	var report io.Writer = io.Discard
+	// priorSize is what earlier runs left in an appended report; ReportSizeBytes
+	// counts only this run's bytes.
+	var priorSize int64
+	// The report is written atomically and only replaces absReport once complete; a
+	// killed run leaves the previous report in place.
+	var reportFile *atomicFile
+	if !opts.jsonOnly && !opts.summaryOnly {
+		if err := os.MkdirAll(filepath.Dir(absReport), 0o755); err != nil {
 			return result, err
 		}
-		caskroomDirs, err := caskroomDirectories(ctx)
-		if err != nil {
+		if reportFile, err = createAtomic(absReport); err != nil {
 			return result, err
 		}
-		if err := writeLines(writer, caskroomDirs); err != nil {
-			return result, err
+		defer reportFile.Close()
+		if opts.appendReport {
+			if priorSize, err = copyExisting(reportFile, absReport); err != nil {
+				return result, err
+			}
 		}
+		report = reportFile
+		result.ReportPath = absReport
 	}
 
-	if err := writeSectionHeader(writer, "HOMEBREW FORMULAE (CLI tools)"); err != nil {
-		return result, err
-	}
-	formulae, err := commandLines(ctx, "brew", "list", "--formula", "--versions")
-	if err != nil {
-		return result, wrapCommandErr("brew list --formula --versions", err, "Confirm Homebrew is installed and formulae are set up.")
+	writer := bufio.NewWriter(report)
+	defer writer.Flush()
+
+	result.StartedAt = time.Now()
+	result.SectionTimings = make(sectionTimings)
+
+	var out io.Writer = writer
+	var redactor *redactingWriter
+	homeDir, _ := os.UserHomeDir()
+	if opts.redactHome {
+		redactor = &redactingWriter{w: writer, r: newHomeRedactor(homeDir)}
+		out = redactor
 	}
-	sort.Strings(formulae)
-	stats.BrewFormulaCount = len(formulae)
-	if err := writeLines(writer, formulae); err != nil {
-		return result, err
+	// With --template the built-in report text is discarded; the template renders the
+	// finished result into the report instead.
+	text := out
+	if opts.reportTemplate != nil {
+		text = io.Discard
 	}
+	result.Compact = opts.compact
+	result.ArchChecked = opts.withArch
+	result.LeavesOnly = opts.leavesOnly
+	result.BrewSkipped = opts.noBrew
+	result.SummaryOnly = opts.summaryOnly
 
-	if !opts.compact {
-		if err := writeSectionHeader(writer, "BREW ENV & METADATA"); err != nil {
-			return result, err
-		}
-		if warn, err := appendCommandOutput(ctx, writer, false, "brew", "config"); err != nil {
-			return result, err
-		} else if warn != "" {
-			result.Warnings = append(result.Warnings, warn)
-		}
-		if warn, err := appendCommandOutput(ctx, writer, true, "brew", "doctor"); err != nil {
+	host := collectHostInfo(ctx)
+	result.Hostname = host.Hostname
+	result.MacOSVersion = host.MacOSVersion
+	result.HardwareModel = host.HardwareModel
+	result.RosettaInstalled = host.RosettaInstalled
+	result.BrewEmulated = host.brewEmulated(result.BrewPath)
+	if priorSize > 0 {
+		result.ReportAppended = true
+		if err := writeRunSeparator(out, result.StartedAt); err != nil {
 			return result, err
-		} else if warn != "" {
-			result.Warnings = append(result.Warnings, warn)
 		}
+	}
+	if err := writeReportHeader(text, result); err != nil {
+		return result, err
+	}
 
-		if err := writeSectionHeader(writer, "FULL BREW PACKAGE METADATA (JSON)"); err != nil {
-			return result, err
-		}
-		if err := writeBrewJSON(ctx, absJSON); err != nil {
-			return result, err
-		}
-		if _, err := fmt.Fprintf(writer, "Saved JSON -> %s\n", absJSON); err != nil {
-			return result, err
-		}
+	stats := exportStats{}
+	run := &exportRun{
+		ctx:      ctx,
+		opts:     opts,
+		w:        text,
+		result:   &result,
+		stats:    &stats,
+		homeDir:  homeDir,
+		jsonPath: absJSON,
+		brewBin:  result.BrewPath,
 	}
 
-	if _, err := fmt.Fprintln(writer); err != nil {
-		return result, err
+	sections := opts.sections
+	if len(sections) == 0 {
+		sections = defaultSectionOrder
 	}
-	if _, err := fmt.Fprintln(writer, "==============================="); err != nil {
-		return result, err
+	var exportErr error
+	if opts.jsonOnly {
+		sections = []string{"brew-json"}
+		exportErr = run.countBrewPackages()
 	}
-	if _, err := fmt.Fprintln(writer, "Report complete!"); err != nil {
-		return result, err
+	if opts.summaryOnly {
+		sections = withoutSection(sections, "brew-json")
+	}
+	// A failed section stops the export (or, under --best-effort, is recorded and
+	// skipped), but the sections written so far are still saved with the footer.
+	if exportErr == nil {
+		exportErr = run.writeSections(sections)
+	}
+	if exportErr == nil {
+		if result.ManifestHash, err = run.computeManifestHash(); err != nil && !run.bestEffort("manifest-hash", err) {
+			exportErr = err
+		}
 	}
-	if _, err := fmt.Fprintf(writer, "Text report: %s\n", absReport); err != nil {
+	stats.TotalItemCount = totalItemCount(stats)
+	if err := writeReportFooter(text, result, opts, exportErr); err != nil {
 		return result, err
 	}
-	if !opts.compact {
-		if _, err := fmt.Fprintf(writer, "JSON metadata: %s\n", absJSON); err != nil {
-			return result, err
+	if opts.reportTemplate != nil {
+		view := result
+		view.Stats = stats
+		view.CommandsRun = commands.list()
+		view.complete(time.Now())
+		if view.BrewJSONPath != "" {
+			view.BrewJSONSizeBytes = fileSize(view.BrewJSONPath)
 		}
-	} else {
-		if _, err := fmt.Fprintln(writer, "JSON metadata: skipped (compact mode)"); err != nil {
+		if err := opts.reportTemplate.Execute(out, view); err != nil {
+			return result, fmt.Errorf("render --template: %w", err)
+		}
+	}
+
+	if redactor != nil {
+		if err := redactor.Flush(); err != nil {
 			return result, err
 		}
 	}
-	if _, err := fmt.Fprintln(writer, "==============================="); err != nil {
+	if err := writer.Flush(); err != nil {
 		return result, err
 	}
+	if reportFile != nil {
+		if err := reportFile.Commit(); err != nil {
+			return result, err
+		}
+	}
 
-	if err := writer.Flush(); err != nil {
-		return result, err
+	if exportErr == nil && !opts.jsonOnly && opts.keepReports > 0 && opts.outputDir != "" {
+		removed, warnings := pruneReports(opts.outputDir, opts.keepReports, absReport)
+		result.PrunedReports = removed
+		result.Warnings = append(result.Warnings, warnings...)
+	}
+	if exportErr == nil && result.ReportPath != "" && opts.outputDir != "" && !opts.noLatestLink {
+		if err := updateLatestLink(opts.outputDir, absReport); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s not updated: %v", latestReportName, err))
+		}
 	}
 
-	result.ReportSizeBytes = fileSize(absReport)
-	if !opts.compact {
-		result.BrewJSONSizeBytes = fileSize(absJSON)
+	if result.ReportPath != "" {
+		result.ReportSizeBytes = fileSize(absReport) - priorSize
+	}
+	if result.BrewJSONPath != "" {
+		result.BrewJSONSizeBytes = fileSize(result.BrewJSONPath)
 	}
 	result.Stats = stats
-	result.CompletedAt = time.Now()
-	result.DurationSeconds = result.CompletedAt.Sub(result.StartedAt).Seconds()
-	result.Compact = opts.compact
+	result.CommandsRun = commands.list()
+	result.complete(time.Now())
+
+	return result, exportErr
+}
 
-	return result, nil
+// complete stamps the result as finished at t. The run time is kept both as
+// DurationSeconds for machines and as a Duration string ("12.412s") for people.
+func (r *exportResult) complete(t time.Time) {
+	r.CompletedAt = t
+	elapsed := t.Sub(r.StartedAt)
+	r.DurationSeconds = elapsed.Seconds()
+	r.Duration = elapsed.Round(time.Millisecond).String()
+}
+
+// writeReportFooter closes the text report. exportErr, when set, marks the report as
+// incomplete.
+func writeReportFooter(w io.Writer, result exportResult, opts exportOptions, exportErr error) error {
+	lines := []string{"", "==============================="}
+	if exportErr != nil {
+		lines = append(lines, "Report incomplete: "+exportErr.Error())
+	} else {
+		lines = append(lines, "Report complete!")
+	}
+	if len(result.FailedSections) > 0 {
+		lines = append(lines, "Failed sections: "+strings.Join(result.FailedSections, ", "))
+	}
+	lines = append(lines, "Text report: "+result.ReportPath)
+	if result.ManifestHash != "" {
+		lines = append(lines, "Manifest hash: "+result.ManifestHash)
+	}
+	switch {
+	case result.BrewJSONPath != "":
+		lines = append(lines, "JSON metadata: "+result.BrewJSONPath)
+	case opts.noBrew:
+		lines = append(lines, "JSON metadata: skipped (--no-brew)")
+	case opts.compact:
+		lines = append(lines, "JSON metadata: skipped (compact mode)")
+	default:
+		lines = append(lines, "JSON metadata: skipped")
+	}
+	lines = append(lines, "===============================")
+	return writeLines(w, lines)
 }
 
 func writeSectionHeader(w io.Writer, title string) error {
@@ -372,7 +1371,7 @@ func writeLines(w io.Writer, lines []string) error {
 }
 
 func commandLines(ctx context.Context, name string, args ...string) ([]string, error) {
-	cmd := exec.CommandContext(ctx, name, args...)
+	cmd := execCommand(ctx, name, args...)
 	cmdOutput, err := cmd.CombinedOutput()
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w: %s", name, err, strings.TrimSpace(string(cmdOutput)))
@@ -387,6 +1386,49 @@ func commandLines(ctx context.Context, name string, args ...string) ([]string, e
 	return lines, nil
 }
 
+// commandStream runs name and calls fn for each trimmed, non-blank stdout line as it
+// arrives, so large outputs are never held in memory at once. Stderr is kept only for
+// the error message.
+func commandStream(ctx context.Context, fn func(line string) error, name string, args ...string) error {
+	cmd := execCommand(ctx, name, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	var fnErr error
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || fnErr != nil {
+			continue
+		}
+		fnErr = fn(line)
+	}
+	scanErr := scanner.Err()
+	if scanErr != nil {
+		// The scanner stopped early (an over-long line); drain the rest so a child still
+		// writing doesn't block on a full pipe and hang Wait.
+		io.Copy(io.Discard, stdout)
+	}
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("%s: %w: %s", name, err, strings.TrimSpace(stderr.String()))
+	}
+	if fnErr != nil {
+		return fnErr
+	}
+	if scanErr != nil {
+		return fmt.Errorf("%s: read output: %w", name, scanErr)
+	}
+	return nil
+}
+
 func listDirSorted(path string) ([]string, error) {
 	entries, err := os.ReadDir(path)
 	if err != nil {
@@ -396,13 +1438,13 @@ func listDirSorted(path string) ([]string, error) {
 	for _, entry := range entries {
 		names = append(names, entry.Name())
 	}
-	sort.Strings(names)
+	sortNatural(names)
 	return names, nil
 }
 
 func appendCommandOutput(ctx context.Context, w io.Writer, allowWarn bool, name string, args ...string) (string, error) {
 	var buf bytes.Buffer
-	cmd := exec.CommandContext(ctx, name, args...)
+	cmd := execCommand(ctx, name, args...)
 	multi := io.MultiWriter(w, &buf)
 	cmd.Stdout = multi
 	cmd.Stderr = multi
@@ -421,69 +1463,141 @@ func appendCommandOutput(ctx context.Context, w io.Writer, allowWarn bool, name
 	return "", nil
 }
 
-func writeBrewJSON(ctx context.Context, path string) error {
-	file, err := os.Create(path)
+// writeBrewJSON saves `brew info` output to path atomically, so a failed or killed
+// run never leaves a truncated JSON file behind.
+func writeBrewJSON(ctx context.Context, brew, path string) error {
+	file, err := createAtomic(path)
 	if err != nil {
 		return err
 	}
 	defer file.Close()
 
 	var stderr bytes.Buffer
-	cmd := exec.CommandContext(ctx, "brew", "info", "--installed", "--json=v2")
+	cmd := execCommand(ctx, brew, "info", "--installed", "--json=v2")
 	cmd.Stdout = file
 	cmd.Stderr = &stderr
 
 	if err := cmd.Run(); err != nil {
 		return wrapCommandErr("brew info --installed --json=v2", err, strings.TrimSpace(stderr.String()))
 	}
-	return nil
+	return file.Commit()
 }
 
-func caskroomDirectories(ctx context.Context) ([]string, error) {
-	prefixLines, err := commandLines(ctx, "brew", "--prefix")
+func brewPrefix(ctx context.Context, brew string) (string, error) {
+	prefixLines, err := commandLines(ctx, brew, "--prefix")
 	if err != nil || len(prefixLines) == 0 {
-		return nil, wrapCommandErr("brew --prefix", err, "")
+		return "", wrapCommandErr("brew --prefix", err, "")
 	}
-	caskroom := filepath.Join(prefixLines[0], "Caskroom")
-	if _, err := os.Stat(caskroom); err != nil {
-		if os.IsNotExist(err) {
-			return []string{}, nil
+	return prefixLines[0], nil
+}
+
+// defaultCaskroomDepth lists each cask and its version folders.
+const defaultCaskroomDepth = 2
+
+// caskroomDirectories walks the Caskroom of every given prefix, listing directories
+// up to maxDepth levels below it. With follow, symlinked directories (the Caskroom
+// itself included) are descended too; a link back into its own ancestry is not
+// followed and is reported in cycles instead.
+func caskroomDirectories(prefixes []string, maxDepth int, follow bool) ([]string, []string, error) {
+	dirs := []string{}
+	var cycles []string
+	for _, prefix := range prefixes {
+		caskroom := filepath.Join(prefix, "Caskroom")
+		if _, err := os.Stat(caskroom); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, nil, err
 		}
-		return nil, err
-	}
 
-	var dirs []string
-	err = filepath.WalkDir(caskroom, func(path string, d os.DirEntry, walkErr error) error {
-		if walkErr != nil {
-			return walkErr
+		if follow {
+			real, err := filepath.EvalSymlinks(caskroom)
+			if err != nil {
+				return nil, nil, err
+			}
+			dirs = append(dirs, caskroom)
+			ancestors := map[string]bool{real: true}
+			if err := walkLinkedDirs(caskroom, 1, maxDepth, ancestors, &dirs, &cycles); err != nil {
+				return nil, nil, err
+			}
+			continue
 		}
-		if !d.IsDir() {
+
+		err := filepath.WalkDir(caskroom, func(path string, d os.DirEntry, walkErr error) error {
+			if walkErr != nil {
+				return walkErr
+			}
+			if !d.IsDir() {
+				return nil
+			}
+			depth := strings.Count(strings.TrimPrefix(path, caskroom), string(os.PathSeparator))
+			if depth > maxDepth {
+				return filepath.SkipDir
+			}
+			dirs = append(dirs, path)
 			return nil
+		})
+		if err != nil {
+			return nil, nil, err
 		}
-		depth := strings.Count(strings.TrimPrefix(path, caskroom), string(os.PathSeparator))
-		if depth > 2 {
-			return filepath.SkipDir
-		}
-		dirs = append(dirs, path)
+	}
+	sortNatural(dirs)
+	return dirs, cycles, nil
+}
+
+// walkLinkedDirs lists the directories below dir, following symlinks, for
+// caskroomDirectories. ancestors holds the resolved paths of the directories being
+// walked; broken links are skipped.
+func walkLinkedDirs(dir string, depth, maxDepth int, ancestors map[string]bool, dirs, cycles *[]string) error {
+	if depth > maxDepth {
 		return nil
-	})
+	}
+	entries, err := os.ReadDir(dir)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	sort.Strings(dirs)
-	return dirs, nil
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		isDir := entry.IsDir()
+		if entry.Type()&os.ModeSymlink != 0 {
+			info, err := os.Stat(path)
+			if err != nil {
+				continue
+			}
+			isDir = info.IsDir()
+		}
+		if !isDir {
+			continue
+		}
+		real, err := filepath.EvalSymlinks(path)
+		if err != nil {
+			continue
+		}
+		if ancestors[real] {
+			*cycles = append(*cycles, fmt.Sprintf("%s -> %s", path, real))
+			continue
+		}
+		*dirs = append(*dirs, path)
+		ancestors[real] = true
+		err = walkLinkedDirs(path, depth+1, maxDepth, ancestors, dirs, cycles)
+		delete(ancestors, real)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func ensureCommand(name, hint string) error {
 	if _, err := exec.LookPath(name); err != nil {
-		return &arcer.CLIError{
+		return withExitCode(exitMissingCommand, &arcer.CLIError{
 			Msg:  fmt.Sprintf("%s is required but not found in PATH", name),
 			Hint: hint,
 			Suggestions: []string{
 				fmt.Sprintf("which %s", name),
 				"echo $PATH",
 			},
-		}
+		})
 	}
 	return nil
 }
@@ -510,32 +1624,216 @@ func fileSize(path string) int64 {
 	return info.Size()
 }
 
+// printSummary writes the table output. Columns are aligned with tabwriter; color is
+// added only when w is a terminal.
 func printSummary(w io.Writer, result exportResult) {
+	style := newSummaryStyle(w)
 	fmt.Fprintf(w, "Apps export completed in %s\n", time.Duration(result.DurationSeconds*float64(time.Second)))
-	fmt.Fprintf(w, "Text report: %s (%s)\n", result.ReportPath, humanize.Bytes(uint64(result.ReportSizeBytes)))
-	if result.BrewJSONPath != "" {
-		fmt.Fprintf(w, "Brew JSON:  %s (%s)\n", result.BrewJSONPath, humanize.Bytes(uint64(result.BrewJSONSizeBytes)))
+	fmt.Fprintf(w, "Host:       %s (macOS %s, %s)\n", valueOrUnknown(result.Hostname), valueOrUnknown(result.MacOSVersion), valueOrUnknown(result.HardwareModel))
+	if result.BrewEmulated {
+		fmt.Fprintln(w, "Rosetta:    brew runs under emulation (x86_64)")
+	}
+	if result.ReportPath != "" {
+		fmt.Fprintf(w, "Text report: %s (%s)\n", result.ReportPath, humanize.Bytes(uint64(result.ReportSizeBytes)))
+	} else if result.SummaryOnly {
+		fmt.Fprintln(w, "Text report: skipped (--summary-only)")
 	} else {
+		fmt.Fprintln(w, "Text report: skipped (--json-only-metadata)")
+	}
+	if result.BrewJSONPath != "" {
+		source := ""
+		if result.BrewJSONFromCache {
+			source = ", from cache"
+		}
+		fmt.Fprintf(w, "Brew JSON:  %s (%s%s)\n", result.BrewJSONPath, humanize.Bytes(uint64(result.BrewJSONSizeBytes)), source)
+	} else if result.SummaryOnly {
+		fmt.Fprintln(w, "Brew JSON:  skipped (--summary-only)")
+	} else if result.BrewSkipped {
+		fmt.Fprintln(w, "Brew JSON:  skipped (Homebrew skipped with --no-brew)")
+	} else if result.Compact {
 		fmt.Fprintln(w, "Brew JSON:  skipped (compact mode)")
+	} else {
+		fmt.Fprintln(w, "Brew JSON:  skipped")
+	}
+
+	if result.DependencyGraphPath != "" {
+		fmt.Fprintf(w, "Deps graph: %s\n", result.DependencyGraphPath)
+	}
+	fmt.Fprintf(w, "Manifest:   %s\n", result.ManifestHash)
+	if n := len(result.PrunedReports); n > 0 {
+		fmt.Fprintf(w, "Pruned:     %d old report(s)\n", n)
+	}
+	for _, uri := range result.UploadedURIs {
+		fmt.Fprintf(w, "Uploaded:   %s\n", uri)
+	}
+	if diff := result.BaselineDiff; diff != nil {
+		fmt.Fprintf(w, "Baseline:   %d added, %d removed since %s\n", len(diff.Added), len(diff.Removed), diff.Path)
+	}
+
+	rows := []summaryRow{
+		{"App bundles (mdfind)", result.Stats.AppBundleCount},
+	}
+	if result.Stats.InaccessibleAppCount > 0 {
+		rows = append(rows, summaryRow{"Inaccessible apps", result.Stats.InaccessibleAppCount})
+	}
+	if result.Stats.ExcludedSystemAppCount > 0 {
+		rows = append(rows, summaryRow{"Excluded system apps", result.Stats.ExcludedSystemAppCount})
+	}
+	rows = append(rows,
+		summaryRow{"/Applications", result.Stats.ApplicationsDirCount},
+		summaryRow{"~/Applications", result.Stats.UserApplicationsCount},
+		summaryRow{"Launchd plists", result.Stats.LaunchItemCount},
+	)
+	if !result.Compact {
+		rows = append(rows, summaryRow{"Login items", result.Stats.LoginItemCount})
+	}
+	rows = append(rows, summaryRow{"Brew casks", result.Stats.BrewCaskCount})
+	if result.Stats.StaleCaskCount > 0 {
+		rows = append(rows, summaryRow{"Stale casks", result.Stats.StaleCaskCount})
+	}
+	if result.LeavesOnly {
+		rows = append(rows, summaryRow{"Brew formulae (req.)", result.Stats.BrewFormulaCount})
+	} else {
+		rows = append(rows, summaryRow{"Brew formulae", result.Stats.BrewFormulaCount})
+	}
+	if !result.Compact {
+		rows = append(rows, summaryRow{"Leaf formulae", result.Stats.LeafFormulaCount})
+	}
+	if result.Stats.MultiVersionFormulaCount > 0 {
+		rows = append(rows, summaryRow{"Multi-version formulae", result.Stats.MultiVersionFormulaCount})
+	}
+	if result.Stats.TapCount > 0 {
+		rows = append(rows, summaryRow{"Formula taps", result.Stats.TapCount})
+	}
+	if result.Stats.DeprecatedFormulaCount > 0 {
+		rows = append(rows, summaryRow{"Deprecated formulae", result.Stats.DeprecatedFormulaCount})
+	}
+	if result.Stats.MacPortsCount > 0 {
+		rows = append(rows, summaryRow{"MacPorts ports", result.Stats.MacPortsCount})
+	}
+	if result.Stats.NixPackageCount > 0 {
+		rows = append(rows, summaryRow{"Nix packages", result.Stats.NixPackageCount})
+	}
+	if result.Stats.UnmanagedAppCount > 0 {
+		rows = append(rows, summaryRow{"Unmanaged apps", result.Stats.UnmanagedAppCount})
+	}
+	if result.Stats.AppConflictCount > 0 {
+		rows = append(rows, summaryRow{"Apps in both folders", result.Stats.AppConflictCount})
+	}
+	if result.Stats.PinnedViolationCount > 0 {
+		rows = append(rows, summaryRow{"Pinned violations", result.Stats.PinnedViolationCount})
+	}
+	if result.Stats.QuarantinedAppCount > 0 {
+		rows = append(rows, summaryRow{"Quarantined apps", result.Stats.QuarantinedAppCount})
+	}
+	if result.Stats.BehindCount > 0 {
+		rows = append(rows, summaryRow{"Formulae behind stable", result.Stats.BehindCount})
+	}
+	if result.Stats.BrowserExtensionCount > 0 {
+		rows = append(rows, summaryRow{"Browser extensions", result.Stats.BrowserExtensionCount})
+	}
+	rows = append(rows, summaryRow{"Go binaries", result.Stats.GoBinaryCount})
+	if !result.Compact {
+		rows = append(rows, summaryRow{"Cargo crates", result.Stats.CargoCrateCount})
+	}
+	rows = append(rows, summaryRow{"Runtime versions", result.Stats.RuntimeVersionCount})
+	if result.ArchChecked {
+		rows = append(rows, summaryRow{"Intel-only", result.Stats.IntelOnlyCount})
 	}
 
-	fmt.Fprintln(w, "\nCounts")
+	fmt.Fprintln(w, "\n"+style.heading("Counts"))
 	fmt.Fprintln(w, strings.Repeat("-", 40))
-	fmt.Fprintf(w, "  App bundles (mdfind): %d\n", result.Stats.AppBundleCount)
-	fmt.Fprintf(w, "  /Applications:        %d\n", result.Stats.ApplicationsDirCount)
-	fmt.Fprintf(w, "  ~/Applications:       %d\n", result.Stats.UserApplicationsCount)
-	fmt.Fprintf(w, "  Brew casks:           %d\n", result.Stats.BrewCaskCount)
-	fmt.Fprintf(w, "  Brew formulae:        %d\n", result.Stats.BrewFormulaCount)
+	writeCountTable(w, style, rows)
+
+	if total := result.Stats.TotalItemCount; total > 0 {
+		fmt.Fprintln(w, "\n"+style.heading(fmt.Sprintf("Sources (sum of rows: %d; cask apps count as app bundles too)", total)))
+		fmt.Fprintln(w, strings.Repeat("-", 40))
+		writeShareTable(w, style, sourceRows(result.Stats), total)
+	}
+
+	if len(result.LargestApps) > 0 {
+		fmt.Fprintf(w, "\n%s\n", style.heading(fmt.Sprintf("Top %d largest apps", len(result.LargestApps))))
+		fmt.Fprintln(w, strings.Repeat("-", 40))
+		sizes := make([]string, len(result.LargestApps))
+		width := 0
+		for i, app := range result.LargestApps {
+			sizes[i] = humanize.Bytes(uint64(app.SizeBytes))
+			width = max(width, len(sizes[i]))
+		}
+		for i, app := range result.LargestApps {
+			fmt.Fprintf(w, "  %*s  %s\n", width, sizes[i], app.Path)
+		}
+	}
+
+	if len(result.SectionTimings) > 0 {
+		names := make([]string, 0, len(result.SectionTimings))
+		for name := range result.SectionTimings {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		fmt.Fprintln(w, "\n"+style.heading("Timings"))
+		fmt.Fprintln(w, strings.Repeat("-", 40))
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for _, name := range names {
+			elapsed := time.Duration(result.SectionTimings[name] * float64(time.Second))
+			fmt.Fprintf(tw, "  %s:\t%s\n", name, elapsed.Round(time.Millisecond))
+		}
+		tw.Flush()
+	}
 
 	if len(result.Warnings) > 0 {
-		fmt.Fprintln(w, "\nWarnings")
+		fmt.Fprintln(w, "\n"+style.warning("Warnings"))
 		fmt.Fprintln(w, strings.Repeat("-", 40))
 		for _, warn := range result.Warnings {
-			fmt.Fprintf(w, "  - %s\n", warn)
+			fmt.Fprintf(w, "  %s %s\n", style.warning("-"), warn)
 		}
 	}
 }
 
+// writeJSONFile writes result as indented JSON to path, creating parent directories.
+func writeJSONFile(path string, result exportResult) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := jsonEncoder(file).Encode(result); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// warningLine is one line of --warnings-file.
+type warningLine struct {
+	Hostname  string    `json:"hostname"`
+	StartedAt time.Time `json:"started_at"`
+	Message   string    `json:"message"`
+}
+
+// writeWarningsFile writes result's warnings as JSON lines, one per warning. A run
+// without warnings leaves an empty file.
+func writeWarningsFile(path string, result exportResult) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(file)
+	for _, warning := range result.Warnings {
+		if err := enc.Encode(warningLine{Hostname: result.Hostname, StartedAt: result.StartedAt, Message: warning}); err != nil {
+			file.Close()
+			return err
+		}
+	}
+	return file.Close()
+}
+
 func jsonEncoder(w io.Writer) *json.Encoder {
 	enc := json.NewEncoder(w)
 	enc.SetIndent("", "  ")
@@ -545,3 +1843,100 @@ func jsonEncoder(w io.Writer) *json.Encoder {
 func yamlEncoder(w io.Writer) *yaml.Encoder {
 	return yaml.NewEncoder(w)
 }
+
+func tomlEncoder(w io.Writer) *toml.Encoder {
+	return toml.NewEncoder(w)
+}
+
+// encodeXML writes result as an indented <export> document.
+func encodeXML(w io.Writer, result exportResult) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.EncodeElement(result, xml.StartElement{Name: xml.Name{Local: "export"}}); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+// sectionTimings maps step names to seconds. encoding/xml can't marshal maps, so it
+// renders as <section name="..." seconds="..."/> elements sorted by name.
+type sectionTimings map[string]float64
+
+func (t sectionTimings) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	names := make([]string, 0, len(t))
+	for name := range t {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, name := range names {
+		elem := xml.StartElement{
+			Name: xml.Name{Local: "section"},
+			Attr: []xml.Attr{
+				{Name: xml.Name{Local: "name"}, Value: name},
+				{Name: xml.Name{Local: "seconds"}, Value: strconv.FormatFloat(t[name], 'f', -1, 64)},
+			},
+		}
+		if err := e.EncodeToken(elem); err != nil {
+			return err
+		}
+		if err := e.EncodeToken(elem.End()); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// stringMap is a string-keyed map that encodes to XML as sorted <entry key="..."> elements.
+type stringMap map[string]string
+
+func (m stringMap) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		elem := xml.StartElement{
+			Name: xml.Name{Local: "entry"},
+			Attr: []xml.Attr{{Name: xml.Name{Local: "key"}, Value: key}},
+		}
+		if err := e.EncodeElement(m[key], elem); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// Output formats arc-apps renders itself in addition to those provided by the SDK.
+const (
+	formatTOML  = "toml"
+	formatXML   = "xml"
+	formatJSONL = "jsonl"
+)
+
+// localFormat returns the --output value when it names a locally rendered format,
+// or "" when the SDK output options should handle it.
+func localFormat(cmd *cobra.Command) string {
+	flag := cmd.Flags().Lookup("output")
+	if flag == nil {
+		return ""
+	}
+	switch format := strings.ToLower(flag.Value.String()); format {
+	case formatTOML, formatXML, formatJSONL:
+		return format
+	default:
+		return ""
+	}
+}
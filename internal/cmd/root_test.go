@@ -0,0 +1,81 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCommandStreamLines(t *testing.T) {
+	var got []string
+	err := commandStream(context.Background(), func(line string) error {
+		got = append(got, line)
+		return nil
+	}, "sh", "-c", `printf ' one \n\ntwo\n'`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0] != "one" || got[1] != "two" {
+		t.Errorf("lines = %q; want [one two]", got)
+	}
+}
+
+func TestWalkLinkedDirsCycle(t *testing.T) {
+	root := t.TempDir()
+	app := filepath.Join(root, "app")
+	if err := os.MkdirAll(filepath.Join(app, "1.0"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// app/1.0/loop points back at app, which would recurse forever without the check.
+	if err := os.Symlink(app, filepath.Join(app, "1.0", "loop")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(root, "missing"), filepath.Join(root, "broken")); err != nil {
+		t.Fatal(err)
+	}
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	realApp := filepath.Join(realRoot, "app")
+
+	var dirs, cycles []string
+	ancestors := map[string]bool{realRoot: true}
+	if err := walkLinkedDirs(root, 1, 10, ancestors, &dirs, &cycles); err != nil {
+		t.Fatal(err)
+	}
+	wantDirs := []string{app, filepath.Join(app, "1.0")}
+	if !reflect.DeepEqual(dirs, wantDirs) {
+		t.Errorf("dirs = %q, want %q", dirs, wantDirs)
+	}
+	wantCycles := []string{filepath.Join(app, "1.0", "loop") + " -> " + realApp}
+	if !reflect.DeepEqual(cycles, wantCycles) {
+		t.Errorf("cycles = %q, want %q", cycles, wantCycles)
+	}
+	if len(ancestors) != 1 {
+		t.Errorf("ancestors not restored: %v", ancestors)
+	}
+}
+
+func TestCommandStreamOverlongLineDoesNotHang(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	// A 2 MiB line exceeds the scanner's 1 MiB limit; the output after it is more than a
+	// pipe buffer, so the child blocks unless commandStream drains it.
+	script := `head -c 2097152 /dev/zero | tr '\0' a; echo; seq 1 200000`
+	err := commandStream(ctx, func(string) error { return nil }, "sh", "-c", script)
+	if ctx.Err() != nil {
+		t.Fatal("commandStream hung on an over-long line")
+	}
+	if !errors.Is(err, bufio.ErrTooLong) {
+		t.Errorf("err = %v; want bufio.ErrTooLong", err)
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+type runtimeVersion struct {
+	Tool    string
+	Version string
+}
+
+type runtimeManager struct {
+	name string
+	args []string
+}
+
+// runtimeManagers lists the version managers checked, in report order.
+var runtimeManagers = []runtimeManager{
+	{name: "asdf", args: []string{"current"}},
+	{name: "mise", args: []string{"ls", "--current"}},
+}
+
+// parseRuntimeVersions reads `asdf current` / `mise ls --current` output, whose lines
+// start with "<tool> <version>". Header rows and tools without a version are skipped.
+func parseRuntimeVersions(lines []string) []runtimeVersion {
+	var versions []runtimeVersion
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || strings.EqualFold(fields[0], "name") || strings.EqualFold(fields[0], "tool") {
+			continue
+		}
+		if strings.Contains(line, "No version is set") || strings.Trim(fields[1], "_") == "" {
+			continue
+		}
+		versions = append(versions, runtimeVersion{Tool: fields[0], Version: fields[1]})
+	}
+	return versions
+}
+
+func runtimeManagersPresent() bool {
+	for _, m := range runtimeManagers {
+		if commandPresent(m.name)() {
+			return true
+		}
+	}
+	return false
+}
+
+func writeRuntimesSection(r *exportRun) error {
+	first := true
+	for _, m := range runtimeManagers {
+		if _, err := exec.LookPath(m.name); err != nil {
+			continue
+		}
+		if !first {
+			if _, err := fmt.Fprintln(r.w); err != nil {
+				return err
+			}
+		}
+		first = false
+
+		cmdStr := strings.Join(append([]string{m.name}, m.args...), " ")
+		if _, err := fmt.Fprintf(r.w, "-- %s --\n", cmdStr); err != nil {
+			return err
+		}
+		lines, err := commandLines(r.ctx, m.name, m.args...)
+		if err != nil {
+			r.warn(fmt.Sprintf("%s failed: %v", cmdStr, err))
+			continue
+		}
+		versions := parseRuntimeVersions(lines)
+		r.stats.RuntimeVersionCount += len(versions)
+		for _, v := range versions {
+			if _, err := fmt.Fprintf(r.w, "%s %s\n", v.Tool, v.Version); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+type cargoCrate struct {
+	Name     string
+	Version  string
+	Binaries []string
+}
+
+// parseCargoInstallList parses `cargo install --list`, where each crate line
+// ("ripgrep v14.1.0:" or "foo v0.1.0 (/src/foo):") is followed by its binaries indented.
+func parseCargoInstallList(lines []string) []cargoCrate {
+	var crates []cargoCrate
+	for _, line := range lines {
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			if len(crates) > 0 {
+				crates[len(crates)-1].Binaries = append(crates[len(crates)-1].Binaries, strings.TrimSpace(line))
+			}
+			continue
+		}
+		fields := strings.Fields(strings.TrimSuffix(strings.TrimSpace(line), ":"))
+		if len(fields) == 0 {
+			continue
+		}
+		crate := cargoCrate{Name: fields[0]}
+		if len(fields) > 1 {
+			crate.Version = fields[1]
+		}
+		crates = append(crates, crate)
+	}
+	return crates
+}
+
+func rustPresent() bool {
+	return commandPresent("cargo")() || commandPresent("rustup")()
+}
+
+func writeRustSection(r *exportRun) error {
+	if _, err := exec.LookPath("rustup"); err == nil {
+		if _, err := fmt.Fprintln(r.w, "-- rustup toolchains --"); err != nil {
+			return err
+		}
+		toolchains, err := commandLines(r.ctx, "rustup", "toolchain", "list")
+		if err != nil {
+			r.warn(fmt.Sprintf("rustup toolchain list failed: %v", err))
+		} else if err := writeLines(r.w, toolchains); err != nil {
+			return err
+		}
+	}
+
+	if _, err := exec.LookPath("cargo"); err != nil {
+		return nil
+	}
+	if _, err := fmt.Fprintln(r.w, "\n-- cargo install --list --"); err != nil {
+		return err
+	}
+	// commandLines trims indentation, which the crate/binary structure depends on.
+	out, err := execCommand(r.ctx, "cargo", "install", "--list").Output()
+	if err != nil {
+		r.warn(fmt.Sprintf("cargo install --list failed: %v", err))
+		return nil
+	}
+	crates := parseCargoInstallList(strings.Split(string(out), "\n"))
+	r.stats.CargoCrateCount = len(crates)
+	for _, crate := range crates {
+		line := strings.TrimSpace(crate.Name + " " + crate.Version)
+		if len(crate.Binaries) > 0 {
+			line = fmt.Sprintf("%s (%s)", line, strings.Join(crate.Binaries, ", "))
+		}
+		if _, err := fmt.Fprintln(r.w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,942 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	arcer "github.com/yourorg/arc-sdk/errors"
+)
+
+// exportRun carries the state shared by report sections during a single export.
+type exportRun struct {
+	ctx      context.Context
+	opts     exportOptions
+	w        io.Writer
+	result   *exportResult
+	stats    *exportStats
+	homeDir  string
+	jsonPath string
+	// brewBin is the brew executable resolved for this run; see brew.
+	brewBin string
+
+	// Collected on first use so sections that depend on each other (such as arch)
+	// work regardless of ordering.
+	appBundles []string
+	casks      []string
+	formulae   []string
+	// rawCasks and rawFormulae are the unfiltered lists, used for cache fingerprints.
+	rawCasks     []string
+	rawFormulae  []string
+	brewPrefixes []string
+	caskTargets  []caskAppTarget
+	formulaTaps  map[string]string
+}
+
+// reportSection is a named block of the text report.
+type reportSection struct {
+	title string
+	// skip reports whether the export options disable the section.
+	skip func(opts exportOptions) bool
+	// present reports whether the tools the section needs are installed. Sections
+	// whose tools are missing are left out of the report silently.
+	present func() bool
+	// needsBrew marks sections that are left out under --no-brew.
+	needsBrew bool
+	write     func(r *exportRun) error
+	// items returns the structured items the section recorded, for --output jsonl.
+	// Sections without it are emitted as their report lines.
+	items func(result *exportResult) any
+}
+
+// defaultSectionOrder lists every section in the order used when --sections is not set.
+var defaultSectionOrder = []string{
+	"apps",
+	"launchd",
+	"login-items",
+	"browser-extensions",
+	"casks",
+	"unmanaged",
+	"formulae",
+	"multi-version",
+	"brew-prefixes",
+	"macports",
+	"nix",
+	"go-binaries",
+	"rust",
+	"runtimes",
+	"arch",
+	"brew-vars",
+	"brew-env",
+	"brew-json",
+	"collectors",
+	"baseline",
+	"pinned",
+}
+
+var reportSections = map[string]reportSection{
+	"apps": {
+		title: "MAC SYSTEM + USER INSTALLED APPLICATIONS (.app bundles)",
+		write: writeAppsSection,
+		items: func(result *exportResult) any { return result.Apps },
+	},
+	"launchd": {
+		title: "LAUNCHD AGENTS & DAEMONS",
+		write: func(r *exportRun) error {
+			count, err := writeLaunchdSection(r.ctx, r.w, r.homeDir, r.opts.verbose)
+			r.stats.LaunchItemCount = count
+			return err
+		},
+	},
+	"login-items": {
+		title: "LOGIN ITEMS",
+		skip:  func(opts exportOptions) bool { return opts.compact },
+		write: writeLoginItemsSection,
+	},
+	"browser-extensions": {
+		title: "BROWSER EXTENSIONS (Safari / Chromium / Firefox)",
+		write: writeBrowserExtensionsSection,
+		items: func(result *exportResult) any { return result.BrowserExtensions },
+	},
+	"casks": {
+		title:     "HOMEBREW CASK APPLICATIONS (GUI)",
+		needsBrew: true,
+		write:     writeCasksSection,
+		items:     func(result *exportResult) any { return result.Casks },
+	},
+	"unmanaged": {
+		title:     "UNMANAGED APPS (not from a Homebrew cask or the App Store)",
+		skip:      func(opts exportOptions) bool { return opts.compact },
+		needsBrew: true,
+		write:     writeUnmanagedSection,
+		items:     func(result *exportResult) any { return result.UnmanagedApps },
+	},
+	"formulae": {
+		title:     "HOMEBREW FORMULAE (CLI tools)",
+		needsBrew: true,
+		write: func(r *exportRun) error {
+			formulae, err := r.loadFormulae()
+			if err != nil {
+				return err
+			}
+			r.stats.BrewFormulaCount = len(formulae)
+			r.result.Formulae = packageItems(formulae)
+			if r.opts.leavesOnly {
+				if _, err := fmt.Fprintln(r.w, "(leaves only: formulae installed on request)"); err != nil {
+					return err
+				}
+			}
+			if r.opts.groupByTap {
+				return r.writeFormulaeByTap(formulae)
+			}
+			return writePackageLines(r.w, formulae, r.opts.packageTables)
+		},
+		items: func(result *exportResult) any { return result.Formulae },
+	},
+	"multi-version": {
+		title:     "FORMULAE WITH MULTIPLE VERSIONS (brew cleanup candidates)",
+		needsBrew: true,
+		write:     writeMultiVersionSection,
+		items:     func(result *exportResult) any { return result.MultiVersionFormulae },
+	},
+	"brew-prefixes": {
+		title:     "HOMEBREW PREFIXES",
+		needsBrew: true,
+		write:     writeBrewPrefixesSection,
+		items:     func(result *exportResult) any { return result.BrewPrefixes },
+	},
+	"macports": {
+		title:   "MACPORTS PORTS",
+		present: commandPresent("port"),
+		write:   writeMacPortsSection,
+		items:   func(result *exportResult) any { return result.MacPorts },
+	},
+	"nix": {
+		title:   "NIX PACKAGES",
+		present: commandPresent("nix"),
+		write:   writeNixSection,
+		items:   func(result *exportResult) any { return result.NixPackages },
+	},
+	"go-binaries": {
+		title:   "GO-INSTALLED BINARIES",
+		present: commandPresent("go"),
+		write:   writeGoBinariesSection,
+	},
+	"rust": {
+		title:   "RUST TOOLCHAINS & CARGO CRATES",
+		skip:    func(opts exportOptions) bool { return opts.compact },
+		present: rustPresent,
+		write:   writeRustSection,
+	},
+	"runtimes": {
+		title:   "LANGUAGE RUNTIMES (asdf / mise)",
+		present: runtimeManagersPresent,
+		write:   writeRuntimesSection,
+	},
+	"arch": {
+		title: "ARCHITECTURES (lipo)",
+		skip:  func(opts exportOptions) bool { return !opts.withArch },
+		write: writeArchReportSection,
+	},
+	"brew-vars": {
+		title:     "HOMEBREW ENVIRONMENT VARIABLES",
+		needsBrew: true,
+		write:     writeBrewVarsSection,
+		items:     func(result *exportResult) any { return result.BrewEnv },
+	},
+	"brew-env": {
+		title: "BREW ENV & METADATA",
+		skip: func(opts exportOptions) bool {
+			return opts.compact
+		},
+		needsBrew: true,
+		write:     writeBrewEnvSection,
+		items:     func(result *exportResult) any { return result.BrewConfig },
+	},
+	"brew-json": {
+		title:     "FULL BREW PACKAGE METADATA (JSON)",
+		skip:      func(opts exportOptions) bool { return opts.compact },
+		needsBrew: true,
+		write:     writeBrewJSONSection,
+	},
+	"collectors": {
+		title: "EXTERNAL COLLECTORS",
+		skip:  func(opts exportOptions) bool { return len(opts.collectors) == 0 },
+		write: writeCollectorsSection,
+		items: func(result *exportResult) any { return result.CollectorItems },
+	},
+	"baseline": {
+		title: "CHANGES SINCE BASELINE",
+		skip:  func(opts exportOptions) bool { return opts.baseline == nil },
+		write: writeBaselineSection,
+		items: func(result *exportResult) any { return result.BaselineDiff },
+	},
+	"pinned": {
+		title:     "PINNED VERSION CHECK",
+		skip:      func(opts exportOptions) bool { return opts.pinned == nil },
+		needsBrew: true,
+		write:     writePinnedSection,
+		items:     func(result *exportResult) any { return result.PinnedViolations },
+	},
+}
+
+// parseSections validates a comma-separated --sections value. An empty value selects
+// every section in the default order; duplicates are ignored.
+func parseSections(raw string) ([]string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return append([]string(nil), defaultSectionOrder...), nil
+	}
+
+	var names []string
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(raw, ",") {
+		name := strings.ToLower(strings.TrimSpace(part))
+		if name == "" || seen[name] {
+			continue
+		}
+		if _, ok := reportSections[name]; !ok {
+			return nil, &arcer.CLIError{
+				Msg:  fmt.Sprintf("unknown report section %q", name),
+				Hint: "Valid sections: " + strings.Join(defaultSectionOrder, ", "),
+				Suggestions: []string{
+					"arc-apps export --sections " + strings.Join(defaultSectionOrder, ","),
+				},
+			}
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return nil, &arcer.CLIError{
+			Msg:  "--sections must name at least one section",
+			Hint: "Valid sections: " + strings.Join(defaultSectionOrder, ", "),
+		}
+	}
+	return names, nil
+}
+
+// withoutSection returns names minus name, leaving names itself untouched.
+func withoutSection(names []string, name string) []string {
+	kept := make([]string, 0, len(names))
+	for _, n := range names {
+		if n != name {
+			kept = append(kept, n)
+		}
+	}
+	return kept
+}
+
+// writeSections runs the selected sections in order, timing each one.
+func (r *exportRun) writeSections(names []string) error {
+	for _, name := range names {
+		section := reportSections[name]
+		if section.skip != nil && section.skip(r.opts) {
+			continue
+		}
+		if section.needsBrew && r.opts.noBrew {
+			continue
+		}
+		if section.present != nil && !section.present() {
+			continue
+		}
+		start := time.Now()
+		if err := writeSectionHeader(r.w, section.title); err != nil {
+			return err
+		}
+		var capture *sectionCapture
+		if r.opts.emit != nil {
+			capture = &sectionCapture{w: r.w}
+			r.w = capture
+		}
+		err := section.write(r)
+		if capture != nil {
+			r.w = capture.w
+		}
+		if err != nil && !r.bestEffort(name, err) {
+			return err
+		}
+		r.track(name, start)
+
+		if capture != nil {
+			var items any = capture.lines()
+			if section.items != nil {
+				items = section.items(r.result)
+			}
+			if err := r.opts.emit(name, items); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// commandPresent returns a present check for a section that needs name on PATH.
+func commandPresent(name string) func() bool {
+	return func() bool {
+		_, err := exec.LookPath(name)
+		return err == nil
+	}
+}
+
+// displayPath rewrites p relative to --relative-to for the text report. Paths outside
+// the base stay absolute.
+func (r *exportRun) displayPath(p string) string {
+	if r.opts.relativeTo == "" || !filepath.IsAbs(p) {
+		return p
+	}
+	rel, err := filepath.Rel(r.opts.relativeTo, p)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, "../") {
+		return p
+	}
+	return rel
+}
+
+// displayPaths applies displayPath to each path. It always returns a new slice, so
+// callers can decorate the lines without touching the cached lists or the result.
+func (r *exportRun) displayPaths(paths []string) []string {
+	out := make([]string, len(paths))
+	for i, p := range paths {
+		out[i] = r.displayPath(p)
+	}
+	return out
+}
+
+func (r *exportRun) track(name string, start time.Time) {
+	r.result.SectionTimings[name] = time.Since(start).Seconds()
+}
+
+func (r *exportRun) warn(msg string) {
+	r.result.Warnings = append(r.result.Warnings, msg)
+}
+
+// bestEffort records a failed section. Under --best-effort the failure becomes a
+// warning and the export continues; otherwise the caller stops with err.
+func (r *exportRun) bestEffort(name string, err error) bool {
+	r.result.FailedSections = append(r.result.FailedSections, name)
+	if !r.opts.bestEffort {
+		return false
+	}
+	r.warn(fmt.Sprintf("section %s failed: %v", name, err))
+	return true
+}
+
+// softFail records err as a warning when --quiet-errors allows an optional
+// section to be skipped. Required sections never call it.
+func (r *exportRun) softFail(err error) bool {
+	if !r.opts.quietErrors {
+		return false
+	}
+	r.warn(err.Error())
+	return true
+}
+
+// strictFail turns a missing or unexpectedly empty location into an error under
+// --strict. Without it the condition stands and nil is returned.
+func (r *exportRun) strictFail(format string, args ...any) error {
+	if !r.opts.strict {
+		return nil
+	}
+	return &arcer.CLIError{
+		Msg:  fmt.Sprintf(format, args...) + " (--strict)",
+		Hint: "Check the machine's setup, or drop --strict to accept empty results.",
+	}
+}
+
+func (r *exportRun) loadAppBundles() ([]string, error) {
+	if r.appBundles != nil {
+		return r.appBundles, nil
+	}
+	// mdfind output is streamed: bundles that can no longer be stat'ed (deleted since
+	// indexing, or behind permissions) are dropped as they arrive, so enrichment only
+	// sees bundles it can read.
+	bundles := []string{}
+	var skipped []string
+	err := commandStream(r.ctx, func(bundle string) error {
+		if r.opts.appExcludes.ignored(bundle) {
+			return nil
+		}
+		if underAny(bundle, r.opts.systemApps) {
+			r.stats.ExcludedSystemAppCount++
+			return nil
+		}
+		if _, err := os.Stat(bundle); err != nil {
+			skipped = append(skipped, bundle)
+			return nil
+		}
+		bundles = append(bundles, bundle)
+		return nil
+	}, "mdfind", r.opts.mdfindArgs()...)
+	if err != nil {
+		return nil, wrapCommandErr("mdfind", err, "")
+	}
+	if len(bundles) == 0 {
+		if err := r.strictFail("mdfind found no app bundles; the Spotlight index may be disabled or rebuilding"); err != nil {
+			return nil, err
+		}
+	}
+	if !r.opts.noSort {
+		sortNatural(bundles)
+	}
+	if r.opts.mergeApps {
+		bundles = r.mergeUserApps(bundles)
+	}
+	if limit := r.opts.maxApps; limit > 0 && len(bundles) > limit {
+		r.warn(fmt.Sprintf("app list truncated to %d bundles by --max-apps; %d omitted", limit, len(bundles)-limit))
+		bundles = bundles[:limit]
+		r.result.TruncatedApps = true
+	}
+
+	r.stats.InaccessibleAppCount = len(skipped)
+	if len(skipped) > 0 {
+		sort.Strings(skipped)
+		r.warn(fmt.Sprintf("skipped %d inaccessible app bundle(s): %s", len(skipped), strings.Join(skipped, ", ")))
+	}
+	r.appBundles = bundles
+	return bundles, nil
+}
+
+// loadCasks, loadFormulae, and loadBrewPrefixes return empty lists under --no-brew so
+// brew-independent sections (arch, manifest hash, watch) keep working.
+func (r *exportRun) loadCasks() ([]string, error) {
+	if r.casks != nil {
+		return r.casks, nil
+	}
+	if r.opts.noBrew {
+		r.rawCasks, r.casks = []string{}, []string{}
+		return r.casks, nil
+	}
+	casks, err := commandLines(r.ctx, r.brew(), "list", "--cask", "--versions")
+	if err != nil {
+		return nil, wrapCommandErr("brew list --cask --versions", err, "Confirm Homebrew is installed and casks are set up.")
+	}
+	if !r.opts.noSort {
+		sortNatural(casks)
+	}
+	r.rawCasks = casks
+	r.casks = r.opts.nameFilter.apply(casks)
+	return r.casks, nil
+}
+
+func (r *exportRun) loadFormulae() ([]string, error) {
+	if r.formulae != nil {
+		return r.formulae, nil
+	}
+	if r.opts.noBrew {
+		r.rawFormulae, r.formulae = []string{}, []string{}
+		return r.formulae, nil
+	}
+	formulae, err := commandLines(r.ctx, r.brew(), "list", "--formula", "--versions")
+	if err != nil {
+		return nil, wrapCommandErr("brew list --formula --versions", err, "Confirm Homebrew is installed and formulae are set up.")
+	}
+	if !r.opts.noSort {
+		sortNatural(formulae)
+	}
+	r.rawFormulae = formulae
+
+	shown := formulae
+	if r.opts.leavesOnly {
+		leaves, err := commandLines(r.ctx, r.brew(), "leaves", "--installed-on-request")
+		if err != nil {
+			return nil, wrapCommandErr("brew leaves --installed-on-request", err, "Upgrade Homebrew or drop --leaves-only.")
+		}
+		shown = onlyPackages(formulae, leaves)
+	}
+	r.formulae = r.opts.nameFilter.apply(shown)
+	return r.formulae, nil
+}
+
+func writeAppsSection(r *exportRun) error {
+	appBundles, err := r.loadAppBundles()
+	if err != nil {
+		return err
+	}
+	r.stats.AppBundleCount = len(appBundles)
+	r.result.Apps = appBundles
+	lines := r.displayPaths(appBundles)
+	if r.opts.withMetadata {
+		start := time.Now()
+		meta, err := appMetadataFor(r.ctx, appBundles, r.opts.concurrency)
+		if err != nil && !r.softFail(err) {
+			return err
+		}
+		if err == nil {
+			r.result.AppMetadata = meta
+			if !r.opts.appTree {
+				for i := range lines {
+					lines[i] = metadataLine(lines[i], meta[i])
+				}
+			}
+		}
+		r.track("app-metadata", start)
+	}
+	if r.opts.appTree {
+		err = writeAppTree(r.w, lines, !r.opts.noSort)
+	} else {
+		err = writeLines(r.w, lines)
+	}
+	if err != nil {
+		return err
+	}
+	if r.opts.withQuarantine {
+		if err := r.writeQuarantinedApps(appBundles); err != nil {
+			return err
+		}
+	}
+
+	if _, err := fmt.Fprintln(r.w); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(r.w, "-- /Applications ---"); err != nil {
+		return err
+	}
+	systemApps, err := listDirSorted("/Applications")
+	if err != nil {
+		return wrapCommandErr("ls /Applications", err, "")
+	}
+	r.stats.ApplicationsDirCount = len(systemApps)
+	if len(systemApps) == 0 {
+		if err := r.strictFail("/Applications is empty"); err != nil {
+			return err
+		}
+	}
+	conflicts := r.appConflictSet()
+	systemLines := systemApps
+	if r.opts.resolveSymlinks {
+		systemLines = r.symlinkLines("/Applications", systemApps)
+	}
+	for i, name := range systemApps {
+		if conflicts[name] {
+			systemLines[i] += " (also in ~/Applications; this copy is used)"
+		}
+	}
+	if err := writeLines(r.w, systemLines); err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintln(r.w); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(r.w, "-- ~/Applications ---"); err != nil {
+		return err
+	}
+	userDir := filepath.Join(r.homeDir, "Applications")
+	userApps, err := listDirSorted(userDir)
+	if err != nil {
+		if err := r.strictFail("cannot list %s: %v", userDir, err); err != nil {
+			return err
+		}
+	} else {
+		if len(conflicts) > 0 {
+			kept := userApps[:0]
+			for _, name := range userApps {
+				if !conflicts[name] {
+					kept = append(kept, name)
+				}
+			}
+			userApps = kept
+		}
+		r.stats.UserApplicationsCount = len(userApps)
+		if r.opts.resolveSymlinks {
+			userApps = r.symlinkLines(userDir, userApps)
+		}
+		if err := writeLines(r.w, userApps); err != nil {
+			return err
+		}
+		if len(conflicts) > 0 {
+			if _, err := fmt.Fprintf(r.w, "(%d duplicate(s) of /Applications entries merged)\n", len(conflicts)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(r.opts.appDirs) > 0 {
+		if err := r.writeExtraAppDirs(); err != nil {
+			return err
+		}
+	}
+
+	if r.opts.withSizes {
+		start := time.Now()
+		if err := r.writeLargestApps(appBundles); err != nil {
+			return err
+		}
+		r.track("app-sizes", start)
+	}
+	return nil
+}
+
+func writeLoginItemsSection(r *exportRun) error {
+	items, warn, err := loginItems(r.ctx)
+	if err != nil && !r.softFail(err) {
+		return err
+	}
+	if warn != "" {
+		r.warn(warn)
+	}
+	r.stats.LoginItemCount = len(items)
+	return writeLines(r.w, items)
+}
+
+// countBrewPackages records the cask and formula counts and lists for
+// --json-only-metadata, which skips the sections that normally set them.
+func (r *exportRun) countBrewPackages() error {
+	casks, err := r.loadCasks()
+	if err != nil {
+		return err
+	}
+	formulae, err := r.loadFormulae()
+	if err != nil {
+		return err
+	}
+	r.stats.BrewCaskCount = len(casks)
+	r.result.Casks = packageItems(casks)
+	r.stats.BrewFormulaCount = len(formulae)
+	r.result.Formulae = packageItems(formulae)
+	return nil
+}
+
+func writeCasksSection(r *exportRun) error {
+	casks, err := r.loadCasks()
+	if err != nil {
+		return err
+	}
+	r.stats.BrewCaskCount = len(casks)
+	r.result.Casks = packageItems(casks)
+	if err := writePackageLines(r.w, casks, r.opts.packageTables); err != nil {
+		return err
+	}
+
+	if r.opts.compact {
+		return nil
+	}
+	pathsStart := time.Now()
+	if _, err := fmt.Fprintln(r.w); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(r.w, "-- Installed paths --"); err != nil {
+		return err
+	}
+	if r.opts.rawCaskroom {
+		caskroomDirs, cycles, err := caskroomDirectories(r.loadBrewPrefixes(), r.opts.caskroomDepth, r.opts.followLinks)
+		if err != nil && !r.softFail(err) {
+			return err
+		}
+		for _, cycle := range cycles {
+			r.warn(fmt.Sprintf("symlink cycle in Caskroom not followed: %s", cycle))
+		}
+		if err := writeLines(r.w, r.displayPaths(caskroomDirs)); err != nil {
+			return err
+		}
+		r.track("caskroom", pathsStart)
+		return nil
+	}
+
+	targets, err := r.loadCaskAppTargets()
+	if err != nil && !r.softFail(err) {
+		return err
+	}
+	for _, target := range targets {
+		if !r.opts.nameFilter.keep(target.Token) {
+			continue
+		}
+		dest := "(no app artifact)"
+		if len(target.Apps) > 0 {
+			dest = strings.Join(r.displayPaths(target.Apps), ", ")
+		}
+		// A cask whose app was deleted by hand stays registered until brew upgrade fails.
+		if missing := missingPaths(target.Apps); len(missing) > 0 {
+			r.stats.StaleCaskCount++
+			r.warn(fmt.Sprintf("cask %s is installed but %s is missing on disk; run `brew reinstall --cask %s` or `brew uninstall --cask %s`",
+				target.Token, strings.Join(missing, ", "), target.Token, target.Token))
+			dest += " (missing)"
+		}
+		if _, err := fmt.Fprintf(r.w, "%s -> %s\n", target.Token, dest); err != nil {
+			return err
+		}
+	}
+	r.track("cask-paths", pathsStart)
+	return nil
+}
+
+// missingPaths returns the paths that do not exist.
+func missingPaths(paths []string) []string {
+	var missing []string
+	for _, p := range paths {
+		if _, err := os.Lstat(p); os.IsNotExist(err) {
+			missing = append(missing, p)
+		}
+	}
+	return missing
+}
+
+func writeArchReportSection(r *exportRun) error {
+	appBundles, err := r.loadAppBundles()
+	if err != nil {
+		return err
+	}
+	formulae, err := r.loadFormulae()
+	if err != nil {
+		return err
+	}
+	// Without a prefix, formulae are reported as unknown rather than failing the section.
+	var prefix string
+	if prefixes := r.loadBrewPrefixes(); len(prefixes) > 0 {
+		prefix = prefixes[0]
+	}
+	intelOnly, err := writeArchSection(r.ctx, r.w, appBundles, packageNames(formulae), prefix, r.displayPath, r.opts.concurrency)
+	r.stats.IntelOnlyCount = intelOnly
+	return err
+}
+
+func writeBrewEnvSection(r *exportRun) error {
+	analyticsStart := time.Now()
+	if err := r.checkBrewAnalytics(); err != nil {
+		return err
+	}
+	r.track("analytics", analyticsStart)
+	if !r.opts.noBrewConfig {
+		configStart := time.Now()
+		var config bytes.Buffer
+		if warn, err := appendCommandOutput(r.ctx, io.MultiWriter(r.w, &config), r.opts.quietErrors, r.brew(), "config"); err != nil {
+			return err
+		} else if warn != "" {
+			r.warn(warn)
+		} else {
+			r.result.BrewConfig = parseBrewConfig(config.String())
+		}
+		r.track("config", configStart)
+	}
+	if r.opts.noDoctor {
+		return nil
+	}
+
+	doctorStart := time.Now()
+	if warn, err := appendCommandOutput(r.ctx, r.w, true, r.brew(), "doctor"); err != nil {
+		return err
+	} else if warn != "" {
+		r.warn(warn)
+	}
+	r.track("doctor", doctorStart)
+	return nil
+}
+
+// writeBrewJSONCached writes the brew JSON, reusing the --cache-dir copy when the
+// installed package set is unchanged. It reports whether the cache was used.
+func (r *exportRun) writeBrewJSONCached() (bool, error) {
+	if r.opts.cacheDir == "" {
+		_, err := r.generateBrewJSON()
+		return false, err
+	}
+
+	if _, err := r.loadCasks(); err != nil {
+		return false, err
+	}
+	if _, err := r.loadFormulae(); err != nil {
+		return false, err
+	}
+	fingerprint := packageFingerprint(r.rawCasks, r.rawFormulae)
+
+	hit, err := restoreCachedBrewJSON(r.opts.cacheDir, fingerprint, r.jsonPath)
+	if err != nil {
+		r.warn(fmt.Sprintf("brew JSON cache unreadable, regenerating: %v", err))
+	}
+	if hit {
+		return true, nil
+	}
+	valid, err := r.generateBrewJSON()
+	if err != nil {
+		return false, err
+	}
+	if !valid {
+		return false, nil
+	}
+	if err := storeCachedBrewJSON(r.opts.cacheDir, fingerprint, r.jsonPath); err != nil {
+		r.warn(fmt.Sprintf("brew JSON cache not updated: %v", err))
+	}
+	return false, nil
+}
+
+// generateBrewJSON runs `brew info` into the JSON path. Under --verify-brew-json it
+// re-runs brew once when the output does not parse; valid is false when it still
+// doesn't, so the file is never cached.
+func (r *exportRun) generateBrewJSON() (valid bool, err error) {
+	if err := writeBrewJSON(r.ctx, r.brew(), r.jsonPath); err != nil {
+		return false, err
+	}
+	if !r.opts.verifyBrewJSON {
+		return true, nil
+	}
+	verr := validateBrewJSON(r.jsonPath)
+	if verr == nil {
+		return true, nil
+	}
+	r.warn(fmt.Sprintf("brew JSON invalid, re-running brew info: %v", verr))
+	if err := writeBrewJSON(r.ctx, r.brew(), r.jsonPath); err != nil {
+		return false, err
+	}
+	if verr := validateBrewJSON(r.jsonPath); verr != nil {
+		r.warn(fmt.Sprintf("brew JSON still invalid after retry: %v", verr))
+		return false, nil
+	}
+	return true, nil
+}
+
+func writeBrewJSONSection(r *exportRun) error {
+	cached, err := r.writeBrewJSONCached()
+	if err != nil {
+		if !r.softFail(err) {
+			return err
+		}
+		_, err := fmt.Fprintln(r.w, "Brew JSON failed (see warnings)")
+		return err
+	}
+	r.result.BrewJSONPath = r.jsonPath
+	r.result.BrewJSONFromCache = cached
+	source := ""
+	if cached {
+		source = " (from cache)"
+	}
+	if _, err := fmt.Fprintf(r.w, "Saved JSON -> %s%s\n", r.jsonPath, source); err != nil {
+		return err
+	}
+
+	info, err := readBrewInfo(r.jsonPath)
+	if err != nil {
+		r.warn(fmt.Sprintf("dependency summary skipped: %v", err))
+		return nil
+	}
+	leafCount, err := writeDependencySummary(r.w, info, r.opts.nameFilter)
+	r.stats.LeafFormulaCount = leafCount
+	if err != nil {
+		return err
+	}
+
+	if r.opts.dependencyGraph {
+		graphPath := strings.TrimSuffix(r.jsonPath, filepath.Ext(r.jsonPath)) + ".dot"
+		if err := writeDependencyGraph(graphPath, info, r.opts.nameFilter); err != nil {
+			if !r.softFail(fmt.Errorf("write dependency graph: %w", err)) {
+				return err
+			}
+		} else {
+			r.result.DependencyGraphPath = graphPath
+			if _, err := fmt.Fprintf(r.w, "\nDependency graph -> %s\n", graphPath); err != nil {
+				return err
+			}
+		}
+	}
+
+	deprecated := deprecatedFormulae(info, r.opts.nameFilter)
+	r.stats.DeprecatedFormulaCount = len(deprecated)
+	r.result.DeprecatedFormulae = deprecated
+	for _, f := range deprecated {
+		r.warn(fmt.Sprintf("formula %s is %s; plan a replacement before Homebrew removes it", f.Name, f.Status))
+	}
+	if err := writeDeprecatedFormulae(r.w, deprecated); err != nil {
+		return err
+	}
+
+	if r.opts.minVersionAge > 0 {
+		behind := behindFormulae(info, r.opts.nameFilter, r.opts.minVersionAge)
+		r.stats.BehindCount = len(behind)
+		r.result.BehindFormulae = behind
+		return writeBehindFormulae(r.w, behind, r.opts.minVersionAge)
+	}
+	return nil
+}
+
+// writeAppTree prints bundles grouped under their parent directories. A directory
+// nested inside an earlier one (such as /Applications/Utilities) is indented beneath
+// it and labelled relative to it. Apps within a directory are sorted unless sorted is
+// false (--no-sort), in which case they keep their listed order.
+func writeAppTree(w io.Writer, bundles []string, sorted bool) error {
+	byDir := make(map[string][]string)
+	for _, bundle := range bundles {
+		dir := filepath.Dir(bundle)
+		byDir[dir] = append(byDir[dir], filepath.Base(bundle))
+	}
+	dirs := make([]string, 0, len(byDir))
+	for dir := range byDir {
+		dirs = append(dirs, dir)
+	}
+	// Compare by path component so a directory always sorts directly before its children.
+	sort.Slice(dirs, func(i, j int) bool {
+		return strings.ReplaceAll(dirs[i], "/", "\x00") < strings.ReplaceAll(dirs[j], "/", "\x00")
+	})
+
+	var parents []string
+	for _, dir := range dirs {
+		for len(parents) > 0 && !strings.HasPrefix(dir, parents[len(parents)-1]+"/") {
+			parents = parents[:len(parents)-1]
+		}
+		label := dir
+		if len(parents) > 0 {
+			label = strings.TrimPrefix(dir, parents[len(parents)-1]+"/")
+		}
+		indent := strings.Repeat("  ", len(parents))
+		if _, err := fmt.Fprintf(w, "%s%s/\n", indent, label); err != nil {
+			return err
+		}
+		apps := byDir[dir]
+		if sorted {
+			sortNatural(apps)
+		}
+		for _, app := range apps {
+			if _, err := fmt.Fprintf(w, "%s  %s\n", indent, app); err != nil {
+				return err
+			}
+		}
+		parents = append(parents, dir)
+	}
+	return nil
+}
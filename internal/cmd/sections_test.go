@@ -0,0 +1,118 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWriteAppTree(t *testing.T) {
+	bundles := []string{
+		"/Applications/Zed.app",
+		"/Applications/Utilities/Tool.app",
+		"/Applications/Arc.app",
+		"/Users/me/Applications/Notes.app",
+	}
+	tests := []struct {
+		sorted bool
+		want   string
+	}{
+		{true, "/Applications/\n  Arc.app\n  Zed.app\n  Utilities/\n    Tool.app\n/Users/me/Applications/\n  Notes.app\n"},
+		// --no-sort keeps each directory's apps in their listed order.
+		{false, "/Applications/\n  Zed.app\n  Arc.app\n  Utilities/\n    Tool.app\n/Users/me/Applications/\n  Notes.app\n"},
+	}
+	for _, tt := range tests {
+		var b strings.Builder
+		if err := writeAppTree(&b, bundles, tt.sorted); err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != tt.want {
+			t.Errorf("writeAppTree(sorted=%v) =\n%s\nwant\n%s", tt.sorted, b.String(), tt.want)
+		}
+	}
+}
+
+// fakeCommand puts an executable shell script named name first on PATH.
+func fakeCommand(t *testing.T, dir, name, script string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWriteAppsSectionMetadataKeepsPaths(t *testing.T) {
+	bin := t.TempDir()
+	xattrLog := filepath.Join(bin, "xattr.log")
+	fakeCommand(t, bin, "mdls", `attr=$5; shift 5
+for p in "$@"; do
+	case $attr in
+	kMDItemVersion) printf '1.2\0' ;;
+	kMDItemKind) printf 'Application\0' ;;
+	*) printf '(\n    Work\n)\0' ;;
+	esac
+done
+`)
+	fakeCommand(t, bin, "xattr", `echo "$3" >> `+xattrLog+`
+echo "xattr: $3: No such xattr: com.apple.quarantine" >&2
+exit 1
+`)
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	bundles := []string{"/Applications/Arc.app", "/Applications/Zed.app"}
+	var w bytes.Buffer
+	r := &exportRun{
+		ctx:        context.Background(),
+		opts:       exportOptions{withMetadata: true, withQuarantine: true, concurrency: 2},
+		w:          &w,
+		result:     &exportResult{SectionTimings: make(sectionTimings)},
+		stats:      &exportStats{},
+		appBundles: append([]string(nil), bundles...),
+	}
+	// The /Applications listing that follows fails off macOS; everything checked
+	// here happens before it.
+	if err := writeAppsSection(r); err != nil && !strings.Contains(err.Error(), "/Applications") {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(w.String(), "/Applications/Arc.app 1.2 (Application) [Work]\n") {
+		t.Errorf("report lacks the metadata line:\n%s", w.String())
+	}
+	if !reflect.DeepEqual(r.result.Apps, bundles) {
+		t.Errorf("result.Apps = %q; want plain paths %q", r.result.Apps, bundles)
+	}
+	if !reflect.DeepEqual(r.appBundles, bundles) {
+		t.Errorf("cached appBundles = %q; want plain paths %q", r.appBundles, bundles)
+	}
+	data, err := os.ReadFile(xattrLog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Quarantine checks run concurrently, so the log order varies.
+	checked := strings.Fields(string(data))
+	sortNatural(checked)
+	if !reflect.DeepEqual(checked, bundles) {
+		t.Errorf("xattr checked %q; want %q", checked, bundles)
+	}
+
+	out, err := json.Marshal(r.result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		Apps []string `json:"apps"`
+	}
+	if err := json.Unmarshal(out, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded.Apps, bundles) {
+		t.Errorf("JSON apps = %q; want plain paths %q", decoded.Apps, bundles)
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+
+	"github.com/dustin/go-humanize"
+)
+
+// largestAppLimit is how many bundles LargestApps and the summary call out.
+const largestAppLimit = 10
+
+type appSize struct {
+	Path      string `json:"path" yaml:"path" toml:"path" xml:"path"`
+	SizeBytes int64  `json:"size_bytes" yaml:"size_bytes" toml:"size_bytes" xml:"size_bytes"`
+}
+
+// bundleSize sums the regular files under path without following symlinks. Entries
+// that can't be read are skipped, so the result is a lower bound on locked-down bundles.
+func bundleSize(path string) int64 {
+	var total int64
+	filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if d != nil && d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				total += info.Size()
+			}
+		}
+		return nil
+	})
+	return total
+}
+
+// largestApps sizes every bundle, up to workers at a time, and returns the biggest,
+// largest first.
+func largestApps(bundles []string, limit, workers int) []appSize {
+	sizes := parallelMap(workers, bundles, func(bundle string) appSize {
+		return appSize{Path: bundle, SizeBytes: bundleSize(bundle)}
+	})
+	sort.SliceStable(sizes, func(i, j int) bool { return sizes[i].SizeBytes > sizes[j].SizeBytes })
+	if len(sizes) > limit {
+		sizes = sizes[:limit]
+	}
+	return sizes
+}
+
+func (r *exportRun) writeLargestApps(bundles []string) error {
+	r.result.LargestApps = largestApps(bundles, largestAppLimit, r.opts.concurrency)
+	if _, err := fmt.Fprintf(r.w, "\n-- Largest apps (top %d) --\n", largestAppLimit); err != nil {
+		return err
+	}
+	for _, app := range r.result.LargestApps {
+		if _, err := fmt.Fprintf(r.w, "%s %s\n", humanize.Bytes(uint64(app.SizeBytes)), r.displayPath(app.Path)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sqliteMigrations build the --sqlite history database. Entry i is schema version i+1;
+// a database records the versions it has applied in schema_migrations, so only newer
+// entries run. Append new entries, never edit old ones: existing databases have
+// already applied them.
+var sqliteMigrations = []string{
+	`CREATE TABLE IF NOT EXISTS runs (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	schema_version TEXT NOT NULL,
+	hostname TEXT NOT NULL,
+	macos_version TEXT,
+	hardware_model TEXT,
+	manifest_hash TEXT,
+	started_at TEXT NOT NULL,
+	completed_at TEXT,
+	duration_seconds REAL
+);
+CREATE TABLE IF NOT EXISTS items (
+	run_id INTEGER NOT NULL REFERENCES runs(id),
+	source TEXT NOT NULL,
+	name TEXT NOT NULL,
+	version TEXT
+);
+CREATE INDEX IF NOT EXISTS items_run_id ON items(run_id);`,
+	`CREATE TABLE IF NOT EXISTS warnings (
+	run_id INTEGER NOT NULL REFERENCES runs(id),
+	message TEXT NOT NULL
+);`,
+	`ALTER TABLE runs ADD COLUMN stats_json TEXT;
+CREATE INDEX IF NOT EXISTS items_source_name ON items(source, name);`,
+}
+
+const sqliteMigrationsTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version INTEGER PRIMARY KEY,
+	applied_at TEXT NOT NULL
+);`
+
+// sqlQuote renders s as an SQL string literal.
+func sqlQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// runSQLite feeds script to the sqlite3 CLI (shipped with macOS) and returns its output.
+func runSQLite(ctx context.Context, db, script string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := execCommand(ctx, "sqlite3", "-bail", db)
+	cmd.Stdin = strings.NewReader(script)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", wrapCommandErr("sqlite3 "+db, err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// sqliteSchemaVersion creates schema_migrations if needed and returns the newest
+// migration the database has applied (0 for a new database).
+func sqliteSchemaVersion(ctx context.Context, db string) (int, error) {
+	out, err := runSQLite(ctx, db, sqliteMigrationsTable+"\nSELECT COALESCE(MAX(version), 0) FROM schema_migrations;\n")
+	if err != nil {
+		return 0, err
+	}
+	version, err := strconv.Atoi(out)
+	if err != nil {
+		return 0, fmt.Errorf("read schema version of %s: unexpected output %q", db, out)
+	}
+	if version > len(sqliteMigrations) {
+		return 0, fmt.Errorf("%s is at schema version %d, newer than this arc-apps supports (%d)", db, version, len(sqliteMigrations))
+	}
+	return version, nil
+}
+
+type sqliteItem struct {
+	source, name, version string
+}
+
+// sqliteItems flattens a result's item lists into rows of the items table.
+func sqliteItems(result exportResult) []sqliteItem {
+	var items []sqliteItem
+	for _, app := range result.Apps {
+		items = append(items, sqliteItem{"app", app, ""})
+	}
+	for _, list := range []struct {
+		source string
+		items  []packageItem
+	}{
+		{"cask", result.Casks},
+		{"formula", result.Formulae},
+		{"macports", result.MacPorts},
+		{"nix", result.NixPackages},
+	} {
+		for _, item := range list.items {
+			items = append(items, sqliteItem{list.source, item.Name, item.version()})
+		}
+	}
+	for _, item := range result.CollectorItems {
+		items = append(items, sqliteItem{"collector:" + item.Collector, item.Name, item.Version})
+	}
+	return items
+}
+
+// sqliteAppendScript returns one transaction that applies the migrations after
+// version current and appends result as a new run with its items and warnings.
+func sqliteAppendScript(current int, result exportResult, now time.Time) (string, error) {
+	stats, err := json.Marshal(result.Stats)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString("BEGIN;\n")
+	for i := current; i < len(sqliteMigrations); i++ {
+		fmt.Fprintf(&b, "%s\nINSERT INTO schema_migrations (version, applied_at) VALUES (%d, %s);\n",
+			sqliteMigrations[i], i+1, sqlQuote(now.UTC().Format(time.RFC3339)))
+	}
+
+	fmt.Fprintf(&b, "INSERT INTO runs (schema_version, hostname, macos_version, hardware_model, manifest_hash, started_at, completed_at, duration_seconds, stats_json) VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s);\n",
+		sqlQuote(result.SchemaVersion),
+		sqlQuote(result.Hostname),
+		sqlQuote(result.MacOSVersion),
+		sqlQuote(result.HardwareModel),
+		sqlQuote(result.ManifestHash),
+		sqlQuote(result.StartedAt.UTC().Format(time.RFC3339)),
+		sqlQuote(result.CompletedAt.UTC().Format(time.RFC3339)),
+		strconv.FormatFloat(result.DurationSeconds, 'f', 3, 64),
+		sqlQuote(string(stats)))
+	// last_insert_rowid() moves with every insert, so pin the run id first.
+	b.WriteString("CREATE TEMP TABLE current_run AS SELECT last_insert_rowid() AS id;\n")
+	for _, item := range sqliteItems(result) {
+		fmt.Fprintf(&b, "INSERT INTO items (run_id, source, name, version) SELECT id, %s, %s, %s FROM current_run;\n",
+			sqlQuote(item.source), sqlQuote(item.name), sqlQuote(item.version))
+	}
+	for _, warning := range result.Warnings {
+		fmt.Fprintf(&b, "INSERT INTO warnings (run_id, message) SELECT id, %s FROM current_run;\n", sqlQuote(warning))
+	}
+	b.WriteString("SELECT id FROM current_run;\nDROP TABLE current_run;\nCOMMIT;\n")
+	return b.String(), nil
+}
+
+// appendSQLite records result as a new run in the --sqlite database, upgrading its
+// schema first. It returns the new run's id.
+func appendSQLite(ctx context.Context, db string, result exportResult) (string, error) {
+	if err := os.MkdirAll(filepath.Dir(db), 0o755); err != nil {
+		return "", err
+	}
+	current, err := sqliteSchemaVersion(ctx, db)
+	if err != nil {
+		return "", err
+	}
+	script, err := sqliteAppendScript(current, result, time.Now())
+	if err != nil {
+		return "", err
+	}
+	return runSQLite(ctx, db, script)
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+)
+
+// summaryStyle adds ANSI emphasis to the table summary when writing to a terminal.
+type summaryStyle struct {
+	color bool
+}
+
+// newSummaryStyle enables color only for terminals (including a --pager showing on
+// one), honoring NO_COLOR and TERM=dumb.
+func newSummaryStyle(w io.Writer) summaryStyle {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok || os.Getenv("TERM") == "dumb" {
+		return summaryStyle{}
+	}
+	if _, ok := w.(*pager); ok {
+		return summaryStyle{color: true}
+	}
+	if !isTerminal(w) {
+		return summaryStyle{}
+	}
+	return summaryStyle{color: true}
+}
+
+func (s summaryStyle) wrap(code, text string) string {
+	if !s.color {
+		return text
+	}
+	return "\x1b[" + code + "m" + text + "\x1b[0m"
+}
+
+func (s summaryStyle) heading(text string) string { return s.wrap("1", text) }
+func (s summaryStyle) warning(text string) string { return s.wrap("33", text) }
+func (s summaryStyle) dim(text string) string     { return s.wrap("2", text) }
+
+type summaryRow struct {
+	label string
+	value int
+}
+
+// writeCountTable prints rows as aligned label/value columns. Values are right-aligned
+// and padded before coloring so escape codes never affect the layout.
+func writeCountTable(w io.Writer, style summaryStyle, rows []summaryRow) error {
+	width := 1
+	for _, row := range rows {
+		if n := len(strconv.Itoa(row.value)); n > width {
+			width = n
+		}
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, row := range rows {
+		value := fmt.Sprintf("%*d", width, row.value)
+		if row.value == 0 {
+			value = style.dim(value)
+		} else {
+			value = style.heading(value)
+		}
+		if _, err := fmt.Fprintf(tw, "  %s:\t%s\n", row.label, value); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
+
+// sourceRows holds one count per place software comes from. Counts that are subsets
+// of another (leaves, stale casks, Intel-only apps, ...) are left out, so the rows
+// add up to TotalItemCount. The sources still overlap: an app installed by a cask is
+// both an app bundle and a cask, so the total is a sum of rows, not distinct items.
+func sourceRows(stats exportStats) []summaryRow {
+	return []summaryRow{
+		{"App bundles", stats.AppBundleCount},
+		{"Brew casks", stats.BrewCaskCount},
+		{"Brew formulae", stats.BrewFormulaCount},
+		{"MacPorts ports", stats.MacPortsCount},
+		{"Nix packages", stats.NixPackageCount},
+		{"Go binaries", stats.GoBinaryCount},
+		{"Cargo crates", stats.CargoCrateCount},
+	}
+}
+
+// totalItemCount sums sourceRows; see there for why it can count an item twice.
+func totalItemCount(stats exportStats) int {
+	total := 0
+	for _, row := range sourceRows(stats) {
+		total += row.value
+	}
+	return total
+}
+
+// writeShareTable prints rows like writeCountTable with each value's share of total
+// appended, e.g. "Brew formulae:  212  48.2%". Empty sources are omitted.
+func writeShareTable(w io.Writer, style summaryStyle, rows []summaryRow, total int) error {
+	width := len(strconv.Itoa(total))
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, row := range rows {
+		if row.value == 0 {
+			continue
+		}
+		share := fmt.Sprintf("%5.1f%%", 100*float64(row.value)/float64(total))
+		if _, err := fmt.Fprintf(tw, "  %s:\t%s  %s\n", row.label, style.heading(fmt.Sprintf("%*d", width, row.value)), style.dim(share)); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
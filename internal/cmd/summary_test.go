@@ -0,0 +1,23 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import "testing"
+
+func TestTotalItemCount(t *testing.T) {
+	tests := []struct {
+		name  string
+		stats exportStats
+		want  int
+	}{
+		{"empty", exportStats{}, 0},
+		{"sums source rows", exportStats{AppBundleCount: 10, BrewCaskCount: 4, BrewFormulaCount: 20, GoBinaryCount: 2}, 36},
+		{"ignores subset counts", exportStats{BrewFormulaCount: 20, LeafFormulaCount: 5, StaleCaskCount: 1, IntelOnlyCount: 3}, 20},
+	}
+	for _, tt := range tests {
+		if got := totalItemCount(tt.stats); got != tt.want {
+			t.Errorf("%s: totalItemCount = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
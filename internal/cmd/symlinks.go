@@ -0,0 +1,57 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// appSymlink is an entry in /Applications or ~/Applications that is a symbolic link.
+type appSymlink struct {
+	Path   string `json:"path" yaml:"path" toml:"path" xml:"path"`
+	Target string `json:"target" yaml:"target" toml:"target" xml:"target"`
+	Broken bool   `json:"broken,omitempty" yaml:"broken,omitempty" toml:"broken,omitempty" xml:"broken,omitempty"`
+}
+
+// resolveAppSymlink reports whether path is a symlink and, if so, where it leads. A
+// link whose target is missing keeps the raw link text and is marked Broken.
+func resolveAppSymlink(path string) (appSymlink, bool) {
+	info, err := os.Lstat(path)
+	if err != nil || info.Mode()&os.ModeSymlink == 0 {
+		return appSymlink{}, false
+	}
+	link := appSymlink{Path: path}
+	if target, err := filepath.EvalSymlinks(path); err == nil {
+		link.Target = target
+		return link, true
+	}
+	link.Broken = true
+	if raw, err := os.Readlink(path); err == nil {
+		link.Target = raw
+	}
+	return link, true
+}
+
+// symlinkLines renders a directory listing with --resolve-symlinks: symlinked entries
+// become "name -> target" (or "name -> target (broken)") and are recorded in the result.
+func (r *exportRun) symlinkLines(dir string, names []string) []string {
+	lines := make([]string, 0, len(names))
+	for _, name := range names {
+		link, ok := resolveAppSymlink(filepath.Join(dir, name))
+		if !ok {
+			lines = append(lines, name)
+			continue
+		}
+		r.result.AppSymlinks = append(r.result.AppSymlinks, link)
+		line := name + " -> " + r.displayPath(link.Target)
+		if link.Broken {
+			line += " (broken)"
+			r.warn(fmt.Sprintf("broken symlink %s -> %s", link.Path, link.Target))
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// rosettaPath exists once Rosetta 2 has been installed on an Apple Silicon Mac.
+const rosettaPath = "/Library/Apple/usr/share/rosetta"
+
+// hostInfo identifies the machine an export came from. Lookups that fail leave
+// their field blank (or false).
+type hostInfo struct {
+	Hostname      string
+	MacOSVersion  string
+	HardwareModel string
+	// AppleSilicon is hw.optional.arm64; Translated is sysctl.proc_translated, set when
+	// arc-apps itself runs under Rosetta.
+	AppleSilicon     bool
+	Translated       bool
+	RosettaInstalled bool
+}
+
+func collectHostInfo(ctx context.Context) hostInfo {
+	var info hostInfo
+	if name, err := os.Hostname(); err == nil {
+		info.Hostname = name
+	}
+	if lines, err := commandLines(ctx, "sw_vers", "-productVersion"); err == nil && len(lines) > 0 {
+		info.MacOSVersion = lines[0]
+	}
+	if lines, err := commandLines(ctx, "sysctl", "-n", "hw.model"); err == nil && len(lines) > 0 {
+		info.HardwareModel = lines[0]
+	}
+	info.AppleSilicon = sysctlFlag(ctx, "hw.optional.arm64")
+	if info.AppleSilicon {
+		info.Translated = sysctlFlag(ctx, "sysctl.proc_translated")
+		_, err := os.Stat(rosettaPath)
+		info.RosettaInstalled = err == nil
+	}
+	return info
+}
+
+// sysctlFlag reads a 0/1 sysctl; keys missing on this hardware read as false.
+func sysctlFlag(ctx context.Context, name string) bool {
+	lines, err := commandLines(ctx, "sysctl", "-n", name)
+	return err == nil && len(lines) > 0 && lines[0] == "1"
+}
+
+// brewEmulated reports whether brew at brewPath runs under Rosetta: either the Intel
+// install under /usr/local on Apple Silicon, or any brew started from a translated
+// arc-apps. It explains x86_64 formulae on an arm64 Mac.
+func (h hostInfo) brewEmulated(brewPath string) bool {
+	if !h.AppleSilicon || brewPath == "" {
+		return false
+	}
+	return h.Translated || strings.HasPrefix(brewPath, "/usr/local/")
+}
+
+// hostnameOf returns the hostname recorded in result, or "unknown-host" when the
+// lookup failed.
+func hostnameOf(result exportResult) string {
+	if result.Hostname == "" {
+		return "unknown-host"
+	}
+	return result.Hostname
+}
+
+func writeReportHeader(w io.Writer, result exportResult) error {
+	lines := []string{
+		"arc-apps inventory report",
+		fmt.Sprintf("Generated: %s", result.StartedAt.Format(time.RFC3339)),
+		fmt.Sprintf("Host:      %s", valueOrUnknown(result.Hostname)),
+		fmt.Sprintf("macOS:     %s", valueOrUnknown(result.MacOSVersion)),
+		fmt.Sprintf("Model:     %s", valueOrUnknown(result.HardwareModel)),
+	}
+	if result.RosettaInstalled || result.BrewEmulated {
+		rosetta := "installed"
+		if !result.RosettaInstalled {
+			rosetta = "not installed"
+		}
+		if result.BrewEmulated {
+			rosetta += "; brew runs under emulation (x86_64)"
+		}
+		lines = append(lines, fmt.Sprintf("Rosetta:   %s", rosetta))
+	}
+	return writeLines(w, lines)
+}
+
+// writeRunSeparator marks where an appended run starts in a report that already holds
+// earlier runs.
+func writeRunSeparator(w io.Writer, started time.Time) error {
+	return writeLines(w, []string{
+		"",
+		"########## arc-apps run appended " + started.Format(time.RFC3339) + " ##########",
+		"",
+	})
+}
+
+func valueOrUnknown(v string) string {
+	if v == "" {
+		return "unknown"
+	}
+	return v
+}
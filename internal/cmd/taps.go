@@ -0,0 +1,109 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// coreTap holds most formulae; --group-by-tap lists it first so third-party taps
+// stand out below it.
+const coreTap = "homebrew/core"
+
+// noTap labels formulae brew reports without a tap, such as ones installed from a
+// local file.
+const noTap = "(no tap)"
+
+// tapGroup is the formulae of one tap, as `brew list --versions` lines.
+type tapGroup struct {
+	Tap   string
+	Lines []string
+}
+
+// loadFormulaTaps maps each installed formula to its tap using
+// `brew info --formula --installed --json=v2`, fetched once per run.
+func (r *exportRun) loadFormulaTaps() (map[string]string, error) {
+	if r.formulaTaps != nil {
+		return r.formulaTaps, nil
+	}
+	var stderr bytes.Buffer
+	cmd := execCommand(r.ctx, r.brew(), "info", "--formula", "--installed", "--json=v2")
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, wrapCommandErr("brew info --formula --installed --json=v2", err, strings.TrimSpace(stderr.String()))
+	}
+	var info brewInfo
+	if err := json.Unmarshal(out, &info); err != nil {
+		return nil, fmt.Errorf("decode brew formula JSON: %w", err)
+	}
+	taps := make(map[string]string, len(info.Formulae))
+	for _, formula := range info.Formulae {
+		taps[formula.Name] = formula.Tap
+	}
+	r.formulaTaps = taps
+	return taps, nil
+}
+
+// groupByTap splits formula lines by tap, keeping each tap's lines in their listed
+// order. homebrew/core comes first, then the other taps by name, then formulae
+// without a tap.
+func groupByTap(lines []string, taps map[string]string) []tapGroup {
+	byTap := make(map[string][]string)
+	for _, line := range lines {
+		name, _, _ := strings.Cut(line, " ")
+		tap := taps[name]
+		if tap == "" {
+			tap = noTap
+		}
+		byTap[tap] = append(byTap[tap], line)
+	}
+	groups := make([]tapGroup, 0, len(byTap))
+	for tap, tapLines := range byTap {
+		groups = append(groups, tapGroup{Tap: tap, Lines: tapLines})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		a, b := groups[i].Tap, groups[j].Tap
+		if (a == coreTap) != (b == coreTap) {
+			return a == coreTap
+		}
+		if (a == noTap) != (b == noTap) {
+			return b == noTap
+		}
+		return naturalLess(a, b)
+	})
+	return groups
+}
+
+// writeFormulaeByTap writes the formulae section under one subheading per tap
+// (--group-by-tap). If the taps can't be read, the flat list is written instead.
+func (r *exportRun) writeFormulaeByTap(formulae []string) error {
+	taps, err := r.loadFormulaTaps()
+	if err != nil {
+		r.warn(fmt.Sprintf("--group-by-tap skipped: %v", err))
+		return writePackageLines(r.w, formulae, r.opts.packageTables)
+	}
+	groups := groupByTap(formulae, taps)
+	for i, group := range groups {
+		if group.Tap != noTap {
+			r.stats.TapCount++
+		}
+		if i > 0 {
+			if _, err := fmt.Fprintln(r.w); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(r.w, "-- %s (%d) --\n", group.Tap, len(group.Lines)); err != nil {
+			return err
+		}
+		if err := writePackageLines(r.w, group.Lines, r.opts.packageTables); err != nil {
+			return err
+		}
+	}
+	return nil
+}
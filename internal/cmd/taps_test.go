@@ -0,0 +1,50 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGroupByTap(t *testing.T) {
+	taps := map[string]string{
+		"wget":      coreTap,
+		"jq":        coreTap,
+		"terraform": "hashicorp/tap",
+		"aws-vault": "99designs/tap",
+		"tap10":     "org/tap10",
+		"tap9":      "org/tap9",
+	}
+	tests := []struct {
+		name  string
+		lines []string
+		want  []tapGroup
+	}{
+		{"empty", nil, []tapGroup{}},
+		{
+			"core first, no tap last, listed order kept",
+			[]string{"wget 1.24.5", "local-tool 0.1", "terraform 1.9.0", "jq 1.7.1", "aws-vault 7.2.0"},
+			[]tapGroup{
+				{Tap: coreTap, Lines: []string{"wget 1.24.5", "jq 1.7.1"}},
+				{Tap: "99designs/tap", Lines: []string{"aws-vault 7.2.0"}},
+				{Tap: "hashicorp/tap", Lines: []string{"terraform 1.9.0"}},
+				{Tap: noTap, Lines: []string{"local-tool 0.1"}},
+			},
+		},
+		{
+			"taps in natural order",
+			[]string{"tap10 1", "tap9 1"},
+			[]tapGroup{
+				{Tap: "org/tap9", Lines: []string{"tap9 1"}},
+				{Tap: "org/tap10", Lines: []string{"tap10 1"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		if got := groupByTap(tt.lines, taps); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: groupByTap = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
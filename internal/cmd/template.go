@@ -0,0 +1,45 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/dustin/go-humanize"
+
+	arcer "github.com/yourorg/arc-sdk/errors"
+)
+
+// templateFuncs are available to --template files in addition to the text/template
+// builtins.
+var templateFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"bytes": func(n int64) string { return humanize.Bytes(uint64(n)) },
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+}
+
+// loadReportTemplate parses a --template file. The template receives the finished
+// exportResult, item lists included, e.g. {{range .Casks}}{{.Name}}{{end}}.
+func loadReportTemplate(path string) (*template.Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, &arcer.CLIError{
+			Msg:  fmt.Sprintf("read --template: %v", err),
+			Hint: "Pass a Go text/template file, e.g. --template report.tmpl.",
+		}
+	}
+	tmpl, err := template.New(filepath.Base(path)).Funcs(templateFuncs).Parse(string(data))
+	if err != nil {
+		return nil, &arcer.CLIError{
+			Msg:  fmt.Sprintf("parse --template: %v", err),
+			Hint: "See https://pkg.go.dev/text/template for the syntax; fields match `arc-apps schema` in Go casing (.Stats.BrewCaskCount).",
+		}
+	}
+	return tmpl, nil
+}
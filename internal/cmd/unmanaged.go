@@ -0,0 +1,75 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// loadCaskAppTargets returns the app bundles each installed cask placed, fetched once
+// per run since both the casks and unmanaged sections need them.
+func (r *exportRun) loadCaskAppTargets() ([]caskAppTarget, error) {
+	if r.caskTargets != nil {
+		return r.caskTargets, nil
+	}
+	if r.opts.noBrew {
+		r.caskTargets = []caskAppTarget{}
+		return r.caskTargets, nil
+	}
+	targets, err := caskAppTargets(r.ctx, r.brew(), "/Applications")
+	if err != nil {
+		return nil, err
+	}
+	r.caskTargets = targets
+	return targets, nil
+}
+
+// fromAppStore reports whether an app bundle carries a Mac App Store receipt.
+func fromAppStore(bundle string) bool {
+	_, err := os.Stat(filepath.Join(bundle, "Contents", "_MASReceipt", "receipt"))
+	return err == nil
+}
+
+// unmanagedApps returns the .app entries of dirs that no installed cask placed and that
+// did not come from the App Store: most likely apps dragged in by hand.
+func unmanagedApps(dirs []string, casks []caskAppTarget) []string {
+	managed := make(map[string]bool)
+	for _, cask := range casks {
+		for _, app := range cask.Apps {
+			managed[filepath.Clean(app)] = true
+		}
+	}
+
+	var found []string
+	for _, dir := range dirs {
+		names, err := listDirSorted(dir)
+		if err != nil {
+			continue
+		}
+		for _, name := range names {
+			if !strings.HasSuffix(name, ".app") {
+				continue
+			}
+			bundle := filepath.Join(dir, name)
+			if managed[bundle] || fromAppStore(bundle) {
+				continue
+			}
+			found = append(found, bundle)
+		}
+	}
+	return found
+}
+
+func writeUnmanagedSection(r *exportRun) error {
+	targets, err := r.loadCaskAppTargets()
+	if err != nil {
+		return err
+	}
+	apps := unmanagedApps([]string{"/Applications", filepath.Join(r.homeDir, "Applications")}, targets)
+	r.stats.UnmanagedAppCount = len(apps)
+	r.result.UnmanagedApps = apps
+	return writeLines(r.w, r.displayPaths(apps))
+}
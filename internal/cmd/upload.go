@@ -0,0 +1,86 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	arcer "github.com/yourorg/arc-sdk/errors"
+)
+
+type s3Target struct {
+	bucket string
+	prefix string
+}
+
+// parseS3URI validates an s3://bucket/prefix destination.
+func parseS3URI(raw string) (s3Target, error) {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "s3" || u.Host == "" {
+		return s3Target{}, &arcer.CLIError{
+			Msg:         fmt.Sprintf("invalid S3 destination %q", raw),
+			Hint:        "Use the form s3://bucket or s3://bucket/prefix.",
+			Suggestions: []string{"arc-apps export --s3 s3://my-bucket/inventory"},
+		}
+	}
+	return s3Target{bucket: u.Host, prefix: strings.Trim(u.Path, "/")}, nil
+}
+
+// objectKey places a file under <prefix>/<hostname>/<timestamp>/ so uploads from many
+// machines and runs never collide.
+func (t s3Target) objectKey(hostname, stamp, file string) string {
+	return path.Join(t.prefix, hostname, stamp, filepath.Base(file))
+}
+
+// uploadExport copies the report and brew JSON to S3 using the default AWS credential
+// chain. It returns the URIs that were written plus a warning for each failure.
+func uploadExport(ctx context.Context, target s3Target, result exportResult) ([]string, []string) {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, []string{fmt.Sprintf("S3 upload skipped: load AWS config: %v", err)}
+	}
+	client := s3.NewFromConfig(cfg)
+
+	hostname := hostnameOf(result)
+	stamp := result.StartedAt.UTC().Format("2006-01-02T15-04-05Z")
+
+	var uris, warnings []string
+	for _, file := range []string{result.ReportPath, result.BrewJSONPath} {
+		if file == "" {
+			continue
+		}
+		key := target.objectKey(hostname, stamp, file)
+		if err := putFile(ctx, client, target.bucket, key, file); err != nil {
+			warnings = append(warnings, fmt.Sprintf("S3 upload of %s failed: %v", file, err))
+			continue
+		}
+		uris = append(uris, fmt.Sprintf("s3://%s/%s", target.bucket, key))
+	}
+	return uris, warnings
+}
+
+func putFile(ctx context.Context, client *s3.Client, bucket, key, file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+		Body:   f,
+	})
+	return err
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	arcer "github.com/yourorg/arc-sdk/errors"
+	"github.com/yourorg/arc-sdk/utils"
+)
+
+func verifyCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "verify <brew.json|->",
+		Short: "Check that installed casks and formulae still match a saved brew JSON",
+		Long: strings.TrimSpace(`
+Recollect the installed casks and formulae and compare them, name and version, with a brew
+JSON file written by 'arc-apps export'. Prints OK and exits 0 when they match; otherwise lists
+each discrepancy and exits non-zero. Pass - to read the brew JSON from stdin.
+`),
+		Example: strings.TrimSpace(`
+Example:
+  # Confirm nothing drifted since the last export
+  arc-apps verify ~/inventory/brew_installed.json
+
+  # Check this machine against another host's dump
+  ssh build-01 cat brew_installed.json | arc-apps verify -
+`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			brew, err := resolveBrew("Install Homebrew from https://brew.sh/ to verify casks and formulae.")
+			if err != nil {
+				return err
+			}
+			path := utils.ExpandPath(args[0])
+			info, err := readBrewInput(path, cmd.InOrStdin())
+			if err != nil {
+				return &arcer.CLIError{
+					Msg:  fmt.Sprintf("read brew JSON: %v", err),
+					Hint: "Pass the file written by `arc-apps export --brew-json-file`.",
+				}
+			}
+			savedCasks, savedFormulae := info.versionLines()
+
+			run := &exportRun{ctx: cmd.Context(), result: &exportResult{}, stats: &exportStats{}, brewBin: brew}
+			casks, err := run.loadCasks()
+			if err != nil {
+				return err
+			}
+			formulae, err := run.loadFormulae()
+			if err != nil {
+				return err
+			}
+
+			saved := append(manifestEntries("cask", savedCasks), manifestEntries("formula", savedFormulae)...)
+			current := append(manifestEntries("cask", casks), manifestEntries("formula", formulae)...)
+			diff := diffSnapshots(saved, current)
+
+			w := cmd.OutOrStdout()
+			if diff.empty() {
+				fmt.Fprintf(w, "OK: %d casks and %d formulae match %s\n", len(casks), len(formulae), inputName(path))
+				return nil
+			}
+			for _, entry := range diff.Added {
+				fmt.Fprintf(w, "+ %s (installed, not in file)\n", strings.TrimSpace(strings.ReplaceAll(entry, "\t", " ")))
+			}
+			for _, entry := range diff.Removed {
+				fmt.Fprintf(w, "- %s (in file, not installed)\n", strings.TrimSpace(strings.ReplaceAll(entry, "\t", " ")))
+			}
+			return &arcer.CLIError{
+				Msg:  fmt.Sprintf("%d discrepancies between installed packages and %s", len(diff.Added)+len(diff.Removed), inputName(path)),
+				Hint: "Run `arc-apps export` to record the current state as the new reference.",
+			}
+		},
+	}
+}
+
+// readBrewInput reads the brew JSON named by a verify argument, from stdin for "-".
+func readBrewInput(path string, stdin io.Reader) (brewInfo, error) {
+	file, err := openInput(path, stdin)
+	if err != nil {
+		return brewInfo{}, err
+	}
+	defer file.Close()
+	return decodeBrewInfo(file, inputName(path))
+}
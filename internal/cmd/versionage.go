@@ -0,0 +1,187 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// behindFormula is an installed formula trailing the tap's stable version.
+type behindFormula struct {
+	Name      string `json:"name" yaml:"name" toml:"name" xml:"name"`
+	Installed string `json:"installed" yaml:"installed" toml:"installed" xml:"installed"`
+	Stable    string `json:"stable" yaml:"stable" toml:"stable" xml:"stable"`
+	// MinorsBehind counts minor releases within the same major; MajorBehind is set
+	// instead when the stable release has a newer major version.
+	MinorsBehind int  `json:"minors_behind,omitempty" yaml:"minors_behind,omitempty" toml:"minors_behind,omitempty" xml:"minors_behind,omitempty"`
+	MajorBehind  bool `json:"major_behind,omitempty" yaml:"major_behind,omitempty" toml:"major_behind,omitempty" xml:"major_behind,omitempty"`
+}
+
+// versionParts reads the leading numeric components of a version ("3.12.1_1" is
+// [3 12 1]). Brew revision suffixes and pre-release tags are ignored; ok is false when
+// the version does not start with a number.
+func versionParts(version string) (parts []int, ok bool) {
+	version, _, _ = strings.Cut(version, "_")
+	for _, field := range strings.Split(version, ".") {
+		end := 0
+		for end < len(field) && field[end] >= '0' && field[end] <= '9' {
+			end++
+		}
+		if end == 0 {
+			break
+		}
+		n, err := strconv.Atoi(field[:end])
+		if err != nil {
+			break
+		}
+		parts = append(parts, n)
+		if end < len(field) {
+			break
+		}
+	}
+	return parts, len(parts) > 0
+}
+
+// versionsBehind compares installed against stable. A missing minor component counts
+// as zero, so "2" vs "2.3" is three minors behind.
+func versionsBehind(installed, stable string) (minors int, major bool, ok bool) {
+	have, ok1 := versionParts(installed)
+	want, ok2 := versionParts(stable)
+	if !ok1 || !ok2 {
+		return 0, false, false
+	}
+	if want[0] != have[0] {
+		return 0, want[0] > have[0], true
+	}
+	minor := func(parts []int) int {
+		if len(parts) > 1 {
+			return parts[1]
+		}
+		return 0
+	}
+	if d := minor(want) - minor(have); d > 0 {
+		return d, false, true
+	}
+	return 0, false, true
+}
+
+// latestInstalled picks the highest version among a formula's installed kegs.
+func latestInstalled(f brewFormula) string {
+	versions := make([]string, 0, len(f.Installed))
+	for _, inst := range f.Installed {
+		versions = append(versions, inst.Version)
+	}
+	return highestVersion(versions)
+}
+
+// highestVersion returns the highest of versions, or "" when there are none. The
+// pinned, version-age, and multi-version checks all pick the current keg with it.
+func highestVersion(versions []string) string {
+	latest := ""
+	for _, version := range versions {
+		if latest == "" || compareVersions(version, latest) > 0 {
+			latest = version
+		}
+	}
+	return latest
+}
+
+// versionRevision returns a brew revision suffix ("3.3.1_1" is 1), or 0 without one.
+func versionRevision(version string) int {
+	_, revision, ok := strings.Cut(version, "_")
+	if !ok {
+		return 0
+	}
+	n, err := strconv.Atoi(revision)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// compareVersions orders two installed versions by their numeric parts and, when
+// those match, by brew revision, so "3.3.1_1" is newer than "3.3.1".
+func compareVersions(a, b string) int {
+	pa, _ := versionParts(a)
+	pb, _ := versionParts(b)
+	if c := compareParts(pa, pb); c != 0 {
+		return c
+	}
+	ra, rb := versionRevision(a), versionRevision(b)
+	switch {
+	case ra < rb:
+		return -1
+	case ra > rb:
+		return 1
+	}
+	return 0
+}
+
+func compareParts(a, b []int) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// behindFormulae returns formulae at least minMinors minor versions behind stable, or a
+// major version behind, sorted by name.
+func behindFormulae(info brewInfo, filter nameFilter, minMinors int) []behindFormula {
+	var found []behindFormula
+	for _, f := range info.Formulae {
+		if !filter.keep(f.Name) || f.Versions.Stable == "" {
+			continue
+		}
+		installed := latestInstalled(f)
+		minors, major, ok := versionsBehind(installed, f.Versions.Stable)
+		if !ok || (!major && minors < minMinors) {
+			continue
+		}
+		found = append(found, behindFormula{
+			Name:         f.Name,
+			Installed:    installed,
+			Stable:       f.Versions.Stable,
+			MinorsBehind: minors,
+			MajorBehind:  major,
+		})
+	}
+	sort.Slice(found, func(i, j int) bool { return naturalLess(found[i].Name, found[j].Name) })
+	return found
+}
+
+// writeBehindFormulae lists formulae trailing their stable release.
+func writeBehindFormulae(w io.Writer, formulae []behindFormula, minMinors int) error {
+	if _, err := fmt.Fprintln(w); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "-- Formulae %d+ minor versions behind stable --\n", minMinors); err != nil {
+		return err
+	}
+	for _, f := range formulae {
+		gap := fmt.Sprintf("%d minor behind", f.MinorsBehind)
+		if f.MajorBehind {
+			gap = "major upgrade"
+		}
+		if _, err := fmt.Fprintf(w, "%s %s -> %s (%s)\n", f.Name, f.Installed, f.Stable, gap); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestVersionParts(t *testing.T) {
+	tests := []struct {
+		version string
+		parts   []int
+		ok      bool
+	}{
+		{"3.12.1", []int{3, 12, 1}, true},
+		{"3.12.1_1", []int{3, 12, 1}, true},
+		{"1.0rc2", []int{1, 0}, true},
+		{"2", []int{2}, true},
+		{"HEAD-abc123", nil, false},
+		{"", nil, false},
+	}
+	for _, tt := range tests {
+		parts, ok := versionParts(tt.version)
+		if !reflect.DeepEqual(parts, tt.parts) || ok != tt.ok {
+			t.Errorf("versionParts(%q) = %v, %v; want %v, %v", tt.version, parts, ok, tt.parts, tt.ok)
+		}
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"3.3.1", "3.3.1", 0},
+		{"3.3.1", "3.3.1_1", -1},
+		{"3.3.1_2", "3.3.1_1", 1},
+		{"3.3.2", "3.3.1_5", 1},
+		{"3.10", "3.9", 1},
+		{"2", "2.0", 0},
+		{"1.2", "1.2.1", -1},
+	}
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d; want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestHighestVersion(t *testing.T) {
+	tests := []struct {
+		versions []string
+		want     string
+	}{
+		{nil, ""},
+		{[]string{"1.2"}, "1.2"},
+		// brew list --versions puts the older keg first.
+		{[]string{"3.3.1", "3.3.1_1"}, "3.3.1_1"},
+		{[]string{"3.3.1_1", "3.3.1"}, "3.3.1_1"},
+		{[]string{"9.0", "10.0", "9.5"}, "10.0"},
+	}
+	for _, tt := range tests {
+		if got := highestVersion(tt.versions); got != tt.want {
+			t.Errorf("highestVersion(%q) = %q; want %q", tt.versions, got, tt.want)
+		}
+	}
+}
+
+func TestVersionsBehind(t *testing.T) {
+	tests := []struct {
+		installed, stable string
+		minors            int
+		major, ok         bool
+	}{
+		{"1.2.0", "1.5.1", 3, false, true},
+		{"2", "2.3", 3, false, true},
+		{"1.9", "2.0", 0, true, true},
+		{"1.5", "1.5_1", 0, false, true},
+		{"HEAD", "1.0", 0, false, false},
+	}
+	for _, tt := range tests {
+		minors, major, ok := versionsBehind(tt.installed, tt.stable)
+		if minors != tt.minors || major != tt.major || ok != tt.ok {
+			t.Errorf("versionsBehind(%q, %q) = %d, %v, %v; want %d, %v, %v",
+				tt.installed, tt.stable, minors, major, ok, tt.minors, tt.major, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+type inventoryDiff struct {
+	Added   []string
+	Removed []string
+}
+
+func (d inventoryDiff) empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0
+}
+
+// collectSnapshot gathers the cheap inventory lists (apps, casks, formulae) so watch mode
+// can decide whether a full export is needed.
+func collectSnapshot(ctx context.Context, opts exportOptions) ([]string, error) {
+	run := &exportRun{ctx: ctx, opts: opts, result: &exportResult{}, stats: &exportStats{}}
+	if !opts.noBrew {
+		brew, err := resolveBrew("Install Homebrew from https://brew.sh/ to capture casks and formulae (or pass --no-brew).")
+		if err != nil {
+			return nil, err
+		}
+		run.brewBin = brew
+	}
+	return run.snapshot()
+}
+
+// snapshot lists the run's apps, casks, and formulae, each entry prefixed with its source.
+func (r *exportRun) snapshot() ([]string, error) {
+	apps, err := r.loadAppBundles()
+	if err != nil {
+		return nil, err
+	}
+	casks, err := r.loadCasks()
+	if err != nil {
+		return nil, err
+	}
+	formulae, err := r.loadFormulae()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := make([]string, 0, len(apps)+len(casks)+len(formulae))
+	for _, app := range apps {
+		snapshot = append(snapshot, "app "+app)
+	}
+	for _, cask := range casks {
+		snapshot = append(snapshot, "cask "+cask)
+	}
+	for _, formula := range formulae {
+		snapshot = append(snapshot, "formula "+formula)
+	}
+	return snapshot, nil
+}
+
+func diffSnapshots(prev, cur []string) inventoryDiff {
+	before := make(map[string]bool, len(prev))
+	for _, entry := range prev {
+		before[entry] = true
+	}
+	after := make(map[string]bool, len(cur))
+	for _, entry := range cur {
+		after[entry] = true
+	}
+
+	var diff inventoryDiff
+	for _, entry := range cur {
+		if !before[entry] {
+			diff.Added = append(diff.Added, entry)
+		}
+	}
+	for _, entry := range prev {
+		if !after[entry] {
+			diff.Removed = append(diff.Removed, entry)
+		}
+	}
+	return diff
+}
+
+func writeDiffSummary(w io.Writer, diff inventoryDiff) {
+	fmt.Fprintf(w, "[%s] inventory changed: %d added, %d removed\n", time.Now().Format(time.RFC3339), len(diff.Added), len(diff.Removed))
+	for _, entry := range diff.Added {
+		fmt.Fprintf(w, "  + %s\n", entry)
+	}
+	for _, entry := range diff.Removed {
+		fmt.Fprintf(w, "  - %s\n", entry)
+	}
+}
+
+// runWatch exports once, then re-checks the inventory every interval and re-exports
+// (overwriting the output files) only when it changed. onExport receives each new
+// result. SIGINT/SIGTERM stop the loop cleanly. Progress goes to logw.
+func runWatch(ctx context.Context, opts exportOptions, interval time.Duration, logw io.Writer, onExport func(exportResult) error) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var prev []string
+	first := true
+	for {
+		snapshot, err := collectSnapshot(ctx, opts)
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case err != nil && first:
+			return err
+		case err != nil:
+			fmt.Fprintf(logw, "[%s] watch: %v\n", time.Now().Format(time.RFC3339), err)
+		default:
+			diff := diffSnapshots(prev, snapshot)
+			if !first && diff.empty() {
+				fmt.Fprintf(logw, "[%s] no inventory changes\n", time.Now().Format(time.RFC3339))
+				break
+			}
+			if !first {
+				writeDiffSummary(logw, diff)
+			}
+			result, err := runExport(ctx, opts)
+			if ctx.Err() != nil {
+				return nil
+			}
+			if err != nil {
+				if first {
+					return err
+				}
+				fmt.Fprintf(logw, "[%s] watch: %v\n", time.Now().Format(time.RFC3339), err)
+				break
+			}
+			if err := onExport(result); err != nil {
+				return err
+			}
+			prev = snapshot
+			first = false
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
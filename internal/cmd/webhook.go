@@ -0,0 +1,80 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	arcer "github.com/yourorg/arc-sdk/errors"
+)
+
+const webhookTimeout = 10 * time.Second
+
+type webhookPayload struct {
+	Hostname        string      `json:"hostname"`
+	Stats           exportStats `json:"stats"`
+	DurationSeconds float64     `json:"duration_seconds"`
+	CompletedAt     time.Time   `json:"completed_at"`
+	Warnings        []string    `json:"warnings,omitempty"`
+}
+
+func validateWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return &arcer.CLIError{
+			Msg:  fmt.Sprintf("invalid webhook URL %q", raw),
+			Hint: "Provide an absolute http:// or https:// URL.",
+		}
+	}
+	return nil
+}
+
+// postWebhook sends the export summary to endpoint. Any failure, including a non-2xx
+// response, is returned as a warning string rather than an error.
+func postWebhook(ctx context.Context, endpoint string, result exportResult) string {
+	payload := webhookPayload{
+		Hostname:        hostnameOf(result),
+		Stats:           result.Stats,
+		DurationSeconds: result.DurationSeconds,
+		CompletedAt:     result.CompletedAt,
+		Warnings:        result.Warnings,
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Sprintf("webhook skipped: encode payload: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Sprintf("webhook failed: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Sprintf("webhook failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		warn := fmt.Sprintf("webhook returned %s", resp.Status)
+		if msg := strings.TrimSpace(string(snippet)); msg != "" {
+			warn = fmt.Sprintf("%s: %s", warn, msg)
+		}
+		return warn
+	}
+	return ""
+}
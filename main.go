@@ -12,6 +12,6 @@ import (
 func main() {
 	root := cmd.NewRootCmd()
 	if err := root.Execute(); err != nil {
-		os.Exit(1)
+		os.Exit(cmd.ExitCode(err))
 	}
 }